@@ -0,0 +1,221 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/unkeep/gomock/gen"
+	"gopkg.in/yaml.v3"
+)
+
+// configNames are the config file names looked up by default.
+var configNames = []string{"gomock.yaml", "gomock.yml", ".gomock.json"}
+
+// config describes the mocks generated by "gomock generate".
+//
+//	package: mocks # package name of files generated into directories without a package
+//	name_pattern: Mock{{.Iface}}
+//	template: mocks.tmpl
+//	mocks:
+//	  - iface: github.com/someone/storage.Storage
+//	    out: storage/mock_storage_test.go
+//	    name: mockStore
+//	  - all: [./cache/...]
+//	    exclude: Internal$
+//	    per_iface: true
+//
+// Relative paths are resolved relative to the config file directory.
+// Mocks with the same "out" file are generated into that single file.
+type config struct {
+	Package     string       `yaml:"package" json:"package"`           // used for directories without a package
+	NamePattern string       `yaml:"name_pattern" json:"name_pattern"` // mock type name pattern, e.g. "Mock{{.Iface}}"
+	Exported    bool         `yaml:"exported" json:"exported"`         // export mock type names
+	Template    string       `yaml:"template" json:"template"`         // custom mock template file
+	Mocks       []mockConfig `yaml:"mocks" json:"mocks"`
+}
+
+// mockConfig describes either a single interface mock or the mocks of
+// every interface of packages like -all option.
+type mockConfig struct {
+	Iface   string `yaml:"iface" json:"iface"`
+	Name    string `yaml:"name" json:"name"`       // mock type name
+	Out     string `yaml:"out" json:"out"`         // output file; output directory for "all"
+	Package string `yaml:"package" json:"package"` // package name of the generated file
+
+	All      []string `yaml:"all" json:"all"` // package patterns
+	Include  string   `yaml:"include" json:"include"`
+	Exclude  string   `yaml:"exclude" json:"exclude"`
+	PerIface bool     `yaml:"per_iface" json:"per_iface"`
+}
+
+// findConfig returns the path of the config file in dir.
+func findConfig(dir string) (string, error) {
+	for _, name := range configNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("config file not found in %s: expected one of %s", dir, strings.Join(configNames, ", "))
+}
+
+// loadConfig reads the config file, YAML or JSON depending on its extension.
+func loadConfig(path string) (config, error) {
+	var cfg config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+
+	if filepath.Ext(path) == ".json" {
+		err = json.Unmarshal(data, &cfg)
+	} else {
+		err = yaml.Unmarshal(data, &cfg)
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("%s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// files returns the files described by the config located in dir.
+// The files are ordered by path, mocks of a file are ordered as in the config.
+func (cfg config) files(dir string) ([]gen.File, error) {
+	// Mocks resolved in the same directory share a loader, so packages are loaded once.
+	loaders := map[string]*gen.Loader{}
+	loader := func(dir string) *gen.Loader {
+		if loaders[dir] == nil {
+			loaders[dir] = newLoader(dir)
+		}
+		return loaders[dir]
+	}
+
+	var all []gen.Interface
+	for i, mc := range cfg.Mocks {
+		out := mc.Out
+		if out != "" && !filepath.IsAbs(out) {
+			out = filepath.Join(dir, out)
+		}
+
+		opts := gen.Options{
+			Package:        mc.Package,
+			DefaultPackage: cfg.Package,
+			NamePattern:    cfg.NamePattern,
+			Exported:       cfg.Exported,
+		}
+
+		var ifaces []gen.Interface
+		switch {
+		case mc.Iface != "" && len(mc.All) == 0:
+			if out == "" {
+				return nil, fmt.Errorf("mocks[%d]: out is required for %s", i, mc.Iface)
+			}
+			srcDir, err := ifaceDir(out, dir)
+			if err != nil {
+				return nil, fmt.Errorf("mocks[%d]: %v", i, err)
+			}
+			l := loader(srcDir)
+			if l.IsPackagePattern(mc.Iface) {
+				return nil, fmt.Errorf("mocks[%d]: iface %s is a package pattern, use all instead", i, mc.Iface)
+			}
+			loaded, err := l.Load(mc.Iface)
+			if err != nil {
+				return nil, fmt.Errorf("mocks[%d]: %v", i, err)
+			}
+			loaded[0].Mock = mc.Name
+			ifaces = loaded
+			opts.Out = out
+		case mc.Iface == "" && len(mc.All) > 0:
+			filter, err := newIfaceFilter(mc.Include, mc.Exclude)
+			if err != nil {
+				return nil, fmt.Errorf("mocks[%d]: %v", i, err)
+			}
+			loaded, err := loader(dir).LoadPackages(mc.All...)
+			if err != nil {
+				return nil, fmt.Errorf("mocks[%d]: %v", i, err)
+			}
+			ifaces = filter.filter(loaded)
+			opts.Dir, opts.PerInterface = out, mc.PerIface
+		default:
+			return nil, fmt.Errorf("mocks[%d]: either iface or all must be set", i)
+		}
+
+		// Mocks named explicitly keep their names.
+		placed, err := gen.Place(ifaces, opts)
+		if err != nil {
+			return nil, fmt.Errorf("mocks[%d]: %v", i, err)
+		}
+		if len(mc.All) > 0 {
+			placed = mockable(placed)
+		}
+		all = append(all, placed...)
+	}
+
+	var opts gen.Options
+	if cfg.Template != "" {
+		path := cfg.Template
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		var err error
+		if opts.Template, err = readTemplate(path); err != nil {
+			return nil, err
+		}
+	}
+	return gen.Generate(all, opts)
+}
+
+// configFiles loads the config given by the -config flag of a subcommand
+// or found in the working directory and returns the files it describes.
+func configFiles(cmd string, args []string) ([]gen.File, error) {
+	flags := flag.NewFlagSet(cmd, flag.ExitOnError)
+	path := flags.String("config", "", "config file; defaults to one of "+strings.Join(configNames, ", "))
+	flags.Parse(args)
+
+	if *path == "" {
+		wd, _ := os.Getwd()
+		var err error
+		if *path, err = findConfig(wd); err != nil {
+			return nil, err
+		}
+	}
+
+	cfg, err := loadConfig(*path)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(*path))
+	if err != nil {
+		return nil, err
+	}
+	return cfg.files(dir)
+}
+
+// generate implements "gomock generate": it generates every mock described by the config.
+func generate(args []string) error {
+	files, err := configFiles("generate", args)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if err := writeFile(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// check implements "gomock check": it fails with a diff if any mock described by the config is stale.
+func check(args []string) error {
+	files, err := configFiles("check", args)
+	if err != nil {
+		return err
+	}
+	return checkFiles(files)
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConfigFiles(t *testing.T) {
+	type file struct {
+		path, pkg string
+		mocks     []string
+	}
+	cases := []struct {
+		dir   string
+		files []file // paths are relative to dir
+	}{
+		{
+			dir: "testdata/config/yaml",
+			files: []file{
+				{"all/mock_store_test.go", "fakes", []string{"FakeGetter"}},
+				{"fakes/store.go", "fakes", []string{"FakeSetter", "FakeGet"}},
+			},
+		},
+		{
+			dir: "testdata/config/json",
+			files: []file{
+				{"mocks/io.go", "mocks", []string{"MockReader"}},
+				{"mocks/mock_getter_test.go", "mocks", []string{"MockGetter"}},
+				{"mocks/mock_setter_test.go", "mocks", []string{"MockSetter"}},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(filepath.Base(c.dir), func(t *testing.T) {
+			dir, err := filepath.Abs(c.dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			path, err := findConfig(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := loadConfig(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			files, err := cfg.files(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []file
+			for _, f := range files {
+				rel, _ := filepath.Rel(dir, f.Path)
+				var mocks []string
+				for _, i := range f.Interfaces {
+					mocks = append(mocks, i.Mock)
+				}
+				got = append(got, file{filepath.ToSlash(rel), f.Package, mocks})
+			}
+			if !reflect.DeepEqual(got, c.files) {
+				t.Fatalf("got files %v, want %v", got, c.files)
+			}
+		})
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		file   string
+		config string
+		err    string
+	}{
+		{
+			name:   "iface_and_all",
+			config: "mocks:\n  - iface: io.Reader\n    all: [./...]\n",
+			err:    "mocks[0]: either iface or all must be set",
+		},
+		{
+			name:   "neither",
+			config: "mocks:\n  - out: mocks.go\n",
+			err:    "mocks[0]: either iface or all must be set",
+		},
+		{
+			name:   "iface_pattern",
+			config: "mocks:\n  - iface: io.Reader\n    out: io.go\n  - iface: ./...\n    out: mocks.go\n",
+			err:    "mocks[1]: iface ./... is a package pattern, use all instead",
+		},
+		{
+			name:   "no_out",
+			config: "mocks:\n  - iface: io.Reader\n",
+			err:    "mocks[0]: out is required for io.Reader",
+		},
+		{
+			name:   "invalid_yaml",
+			config: "mocks: {",
+			err:    "gomock.yaml: yaml: ",
+		},
+		{
+			name:   "invalid_json",
+			file:   ".gomock.json",
+			config: `{"mocks": [}`,
+			err:    ".gomock.json: invalid character",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir := t.TempDir()
+			file := c.file
+			if file == "" {
+				file = "gomock.yaml"
+			}
+			if err := os.WriteFile(filepath.Join(dir, file), []byte(c.config), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			path, err := findConfig(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := loadConfig(path)
+			if err == nil {
+				_, err = cfg.files(dir)
+			}
+			if err == nil || !strings.Contains(err.Error(), c.err) {
+				t.Fatalf("got error %v, want %s", err, c.err)
+			}
+		})
+	}
+
+	if _, err := findConfig(t.TempDir()); err == nil || !strings.Contains(err.Error(), "config file not found") {
+		t.Fatalf("got error %v, want config file not found", err)
+	}
+}
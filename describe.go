@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/unkeep/gomock/gen"
+)
+
+// describe implements "gomock describe": it prints the method sets of interfaces,
+// given as interfaces or package patterns, as a JSON array of gen.Description.
+func describe(args []string) error {
+	flags := flag.NewFlagSet("describe", flag.ExitOnError)
+	format := flags.String("format", "json", "output format; only json is supported")
+	flags.Parse(args)
+
+	if *format != "json" {
+		return fmt.Errorf("unsupported format %q, want json", *format)
+	}
+	if flags.NArg() == 0 {
+		return fmt.Errorf("describe requires interfaces or package patterns")
+	}
+
+	wd, _ := os.Getwd()
+	l := newLoader(wd)
+	descs := []gen.Description{}
+	for _, arg := range flags.Args() {
+		ifaces, err := l.Load(arg)
+		if err != nil {
+			return err
+		}
+		for _, i := range ifaces {
+			descs = append(descs, gen.Describe(i))
+		}
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "\t")
+	return enc.Encode(descs)
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines around changes in a unified diff.
+const diffContext = 3
+
+// edit is a line of a diff: ' ' unchanged, '-' deleted or '+' inserted.
+type edit struct {
+	op   byte
+	line string
+}
+
+// unifiedDiff returns the unified diff of texts a and b, or "" if they are equal.
+func unifiedDiff(nameA, nameB, a, b string) string {
+	if a == b {
+		return ""
+	}
+
+	edits := diffLines(splitLines(a), splitLines(b))
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "--- %s\n+++ %s\n", nameA, nameB)
+
+	// Line numbers of the edits in a and b.
+	posA := make([]int, len(edits)+1)
+	posB := make([]int, len(edits)+1)
+	for i, e := range edits {
+		posA[i+1], posB[i+1] = posA[i], posB[i]
+		if e.op != '+' {
+			posA[i+1]++
+		}
+		if e.op != '-' {
+			posB[i+1]++
+		}
+	}
+
+	for i := 0; i < len(edits); {
+		if edits[i].op == ' ' {
+			i++
+			continue
+		}
+
+		// Extend the hunk while changes are closer than twice the context.
+		start := max(i-diffContext, 0)
+		end := i
+		for j := i; j < len(edits); j++ {
+			if edits[j].op != ' ' {
+				end = j + 1
+			} else if j-end >= 2*diffContext {
+				break
+			}
+		}
+		end = min(end+diffContext, len(edits))
+
+		fmt.Fprintf(&buf, "@@ -%s +%s @@\n",
+			hunkRange(posA[start], posA[end]), hunkRange(posB[start], posB[end]))
+		for _, e := range edits[start:end] {
+			fmt.Fprintf(&buf, "%c%s\n", e.op, e.line)
+		}
+		i = end
+	}
+	return buf.String()
+}
+
+// hunkRange formats the lines [from, to) range of a hunk header.
+func hunkRange(from, to int) string {
+	if from == to {
+		return fmt.Sprintf("%d,0", from)
+	}
+	return fmt.Sprintf("%d,%d", from+1, to-from)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines returns the edits transforming a into b.
+// Lines of the common prefix and suffix are kept as is and
+// the lines between them are compared by the Myers algorithm.
+func diffLines(a, b []string) []edit {
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		suf++
+	}
+
+	var edits []edit
+	for _, line := range a[:pre] {
+		edits = append(edits, edit{' ', line})
+	}
+	edits = append(edits, shortestEdits(a[pre:len(a)-suf], b[pre:len(b)-suf])...)
+	for _, line := range a[len(a)-suf:] {
+		edits = append(edits, edit{' ', line})
+	}
+	return edits
+}
+
+// shortestEdits returns the shortest edits transforming a into b found by the Myers algorithm
+// in O((len(a)+len(b))*d) time, d being the number of deleted and inserted lines.
+func shortestEdits(a, b []string) []edit {
+	n, m := len(a), len(b)
+	if n+m == 0 {
+		return nil
+	}
+
+	// v[off+k] is the furthest line of a reached on the diagonal k = x-y;
+	// trace[d][d+k] is v after d edits.
+	off := n + m + 1
+	v := make([]int, 2*off+1)
+	var trace [][]int
+	var x, y int
+	for d := 0; ; d++ {
+		for k := -d; k <= d; k += 2 {
+			if k == -d || k != d && v[off+k-1] < v[off+k+1] {
+				x = v[off+k+1] // insertion of b[y-1]
+			} else {
+				x = v[off+k-1] + 1 // deletion of a[x-1]
+			}
+			y = x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				return backtrackEdits(a, b, trace, d)
+			}
+		}
+		trace = append(trace, append([]int(nil), v[off-d:off+d+1]...))
+	}
+}
+
+// backtrackEdits returns the edits of the path of d edits found by shortestEdits.
+func backtrackEdits(a, b []string, trace [][]int, d int) []edit {
+	var edits []edit
+	x, y := len(a), len(b)
+	for ; d > 0; d-- {
+		prev := trace[d-1] // prev[d-1+k] is the furthest x on the diagonal k
+		k := x - y
+		prevK := k - 1
+		if k == -d || k != d && prev[d-1+k-1] < prev[d-1+k+1] {
+			prevK = k + 1
+		}
+		prevX := prev[d-1+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			edits = append(edits, edit{' ', a[x]})
+		}
+		if prevK == k+1 {
+			edits = append(edits, edit{'+', b[prevY]})
+		} else {
+			edits = append(edits, edit{'-', a[prevX]})
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 {
+		x--
+		edits = append(edits, edit{' ', a[x]})
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
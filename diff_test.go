@@ -0,0 +1,94 @@
+package main
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	cases := []struct {
+		name string
+		a, b string
+		diff string
+	}{
+		{
+			name: "equal",
+			a:    "a\nb\n",
+			b:    "a\nb\n",
+			diff: "",
+		},
+		{
+			name: "missing",
+			a:    "",
+			b:    "a\nb\n",
+			diff: "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name: "changed",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			b:    "1\n2\n3\n4\nx\n6\n7\n8\n9\n",
+			diff: "--- a\n+++ b\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+x\n 6\n 7\n 8\n",
+		},
+		{
+			name: "separate_hunks",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			b:    "x\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			diff: "--- a\n+++ b\n@@ -1,4 +1,4 @@\n-1\n+x\n 2\n 3\n 4\n@@ -7,4 +7,3 @@\n 7\n 8\n 9\n-10\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if diff := unifiedDiff("a", "b", c.a, c.b); diff != c.diff {
+				t.Fatalf("Diff expected:\n%s\ngot:\n%s", c.diff, diff)
+			}
+		})
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	large := make([]string, 20000)
+	for i := range large {
+		large[i] = strconv.Itoa(i)
+	}
+	changed := append(append(append([]string(nil), large[:100]...), "x"), large[100:19000]...)
+	changed = append(append(changed, "y"), large[19001:]...)
+
+	cases := []struct {
+		name  string
+		a, b  []string
+		edits int
+	}{
+		{name: "empty"},
+		{name: "deleted", a: []string{"a", "b"}, edits: 2},
+		{name: "inserted", b: []string{"a", "b"}, edits: 2},
+		{name: "replaced", a: []string{"a", "b", "c"}, b: []string{"a", "x", "c"}, edits: 2},
+		{name: "interleaved", a: []string{"a", "b", "c", "a", "b", "b", "a"}, b: []string{"c", "b", "a", "b", "a", "c"}, edits: 5},
+		{name: "large", a: large, b: changed, edits: 3},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var a, b []string
+			edits := 0
+			for _, e := range diffLines(c.a, c.b) {
+				if e.op != '+' {
+					a = append(a, e.line)
+				}
+				if e.op != '-' {
+					b = append(b, e.line)
+				}
+				if e.op != ' ' {
+					edits++
+				}
+			}
+			if !reflect.DeepEqual(a, c.a) || !reflect.DeepEqual(b, c.b) {
+				t.Fatalf("edits don't transform a into b: got %v and %v", a, b)
+			}
+			if edits != c.edits {
+				t.Fatalf("expected %d edits, got %d", c.edits, edits)
+			}
+		})
+	}
+}
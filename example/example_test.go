@@ -35,6 +35,13 @@ type mockStorage struct {
 	mock.M
 }
 
+var _ Storage = (*mockStorage)(nil)
+
+// newMockStorage returns a new mockStorage which checks its expectations when the test finishes
+func newMockStorage(t mock.TestingT, opts ...mock.Option) *mockStorage {
+	return &mockStorage{mock.New(t, append([]mock.Option{mock.CheckOnCleanup()}, opts...)...)}
+}
+
 func (m *mockStorage) GetValue(key string) (val int, err error) {
 	mock.Call(m, Storage.GetValue, key).Return(&val, &err)
 	return
@@ -45,6 +52,45 @@ func (m *mockStorage) SetValue(key string, value int) (err error) {
 	return
 }
 
+// EXPECT returns the typed recorder of calls which must be made during the test
+func (m *mockStorage) EXPECT() *mockStorageRecorder {
+	return &mockStorageRecorder{m, mock.ExpectCall}
+}
+
+// ON returns the typed recorder of calls which can be made during the test
+func (m *mockStorage) ON() *mockStorageRecorder {
+	return &mockStorageRecorder{m, mock.OnCall}
+}
+
+type mockStorageRecorder struct {
+	m       *mockStorage
+	declare func(obj interface{}, f interface{}, args ...interface{}) mock.Returner
+}
+
+func (r *mockStorageRecorder) GetValue(key mock.Arg[string]) mockStorageGetValueCall {
+	return mockStorageGetValueCall{r.declare(r.m, Storage.GetValue, key)}
+}
+
+type mockStorageGetValueCall struct {
+	r mock.Returner
+}
+
+func (c mockStorageGetValueCall) Return(out1 int, out2 error) {
+	c.r.Return(out1, out2)
+}
+
+func (r *mockStorageRecorder) SetValue(key mock.Arg[string], value mock.Arg[int]) mockStorageSetValueCall {
+	return mockStorageSetValueCall{r.declare(r.m, Storage.SetValue, key, value)}
+}
+
+type mockStorageSetValueCall struct {
+	r mock.Returner
+}
+
+func (c mockStorageSetValueCall) Return(out1 error) {
+	c.r.Return(out1)
+}
+
 // testIncrementValue a test of incrementValue function with using mocked storage interface
 func TestIncrementValue(t *testing.T) {
 	cases := []struct {
@@ -99,3 +145,22 @@ func TestIncrementValue(t *testing.T) {
 		})
 	}
 }
+
+// TestIncrementValueWithRecorder the same test using the typed recorder generated by gomock tool
+// The expectations are checked when the test finishes.
+func TestIncrementValueWithRecorder(t *testing.T) {
+	st := newMockStorage(t)
+
+	st.EXPECT().GetValue(mock.Eq("key")).Return(123, nil)
+	st.EXPECT().SetValue(mock.Eq("key"), mock.Any[int]()).Return(nil)
+
+	newVal, err := IncrementValue("key", st)
+
+	if err != nil {
+		t.Fatalf(`Error not expected, got "%v"`, err)
+	}
+
+	if newVal != 124 {
+		t.Fatalf(`Value expected: "%d", got: "%d"`, 124, newVal)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/unkeep/gomock/gen"
+)
+
+// extract implements "gomock extract": it declares an interface with the method set
+// of a type and optionally generates its mock using opts.
+func extract(args []string, opts gen.Options) error {
+	flags := flag.NewFlagSet("extract", flag.ExitOnError)
+	methods := flags.String("methods", "", "comma-separated methods of the interface; defaults to all exported ones")
+	name := flags.String("name", "", `interface name; defaults to the type name suffixed by "API"`)
+	pkgName := flags.String("package", "", "package name of the interface file")
+	dest := flags.String("o", "", "file to declare the interface in; defaults to stdout")
+	mockOut := flags.String("mock", "", "file to generate the mock of the interface into")
+	flags.Parse(args)
+
+	// Options may follow the type: gomock extract pkg.Client -name ClientAPI.
+	var typ string
+	if flags.NArg() > 0 {
+		typ = flags.Arg(0)
+		flags.Parse(flags.Args()[1:])
+	}
+	if typ == "" || flags.NArg() > 0 {
+		return fmt.Errorf("extract requires a single type")
+	}
+
+	e := gen.Extraction{Type: typ, Name: *name, Methods: splitMethods(*methods), Out: *dest, Package: *pkgName}
+	wd, _ := os.Getwd()
+	if e.Out == "" {
+		// The interface printed to stdout is declared in the package of the working directory.
+		e.Out = filepath.Join(wd, "iface_stdout.go")
+	}
+
+	l, err := ifaceLoader(e.Out)
+	if err != nil {
+		return err
+	}
+	iface, f, err := l.Extract(e)
+	if err != nil {
+		return err
+	}
+	if *dest == "" {
+		if _, err := os.Stdout.Write(f.Content); err != nil {
+			return err
+		}
+	} else if err := writeFile(f); err != nil {
+		return err
+	}
+
+	if *mockOut == "" {
+		return nil
+	}
+	opts.Out = *mockOut
+	if mockDir, err := filepath.Abs(filepath.Dir(*mockOut)); err == nil && mockDir == filepath.Dir(f.Path) && opts.Package == "" {
+		// The directory may have no package until the interface file is written.
+		opts.Package = f.Package
+	}
+	files, err := gen.Generate([]gen.Interface{iface}, opts)
+	if err != nil {
+		return err
+	}
+	return writeFile(files[0])
+}
+
+// splitMethods returns the methods of the comma-separated list, e.g. "Do, Get".
+func splitMethods(list string) []string {
+	var methods []string
+	for _, m := range strings.Split(list, ",") {
+		if m = strings.TrimSpace(m); m != "" {
+			methods = append(methods, m)
+		}
+	}
+	return methods
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitMethods(t *testing.T) {
+	cases := map[string][]string{
+		"":          nil,
+		"Do":        {"Do"},
+		"Do,Get":    {"Do", "Get"},
+		"Do, Get":   {"Do", "Get"},
+		" Do ,Get,": {"Do", "Get"},
+	}
+	for list, want := range cases {
+		if got := splitMethods(list); !reflect.DeepEqual(got, want) {
+			t.Errorf("splitMethods(%q) = %q, want %q", list, got, want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/unkeep/gomock/gen"
+)
+
+// writeFile writes the generated file to its path.
+func writeFile(f gen.File) error {
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(f.Path, f.Content, 0644)
+}
+
+// readTemplate parses the custom mock template in the file.
+func readTemplate(path string) (*gen.Template, error) {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return gen.ParseTemplate(path, string(text))
+}
+
+// ifaceFilter selects interfaces by name.
+type ifaceFilter struct {
+	include *regexp.Regexp // nil matches all
+	exclude *regexp.Regexp // nil matches none
+}
+
+func newIfaceFilter(include, exclude string) (ifaceFilter, error) {
+	var f ifaceFilter
+	var err error
+	if include != "" {
+		if f.include, err = regexp.Compile(include); err != nil {
+			return f, fmt.Errorf("invalid -include: %v", err)
+		}
+	}
+	if exclude != "" {
+		if f.exclude, err = regexp.Compile(exclude); err != nil {
+			return f, fmt.Errorf("invalid -exclude: %v", err)
+		}
+	}
+	return f, nil
+}
+
+func (f ifaceFilter) match(name string) bool {
+	return (f.include == nil || f.include.MatchString(name)) &&
+		(f.exclude == nil || !f.exclude.MatchString(name))
+}
+
+// filter returns the interfaces of ifaces matching f.
+func (f ifaceFilter) filter(ifaces []gen.Interface) []gen.Interface {
+	var res []gen.Interface
+	for _, i := range ifaces {
+		if f.match(i.Name) {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// checkFile compares the generated file to the file on disk.
+// It returns the unified diff of the files, empty if the file is up to date.
+func checkFile(f gen.File) (string, error) {
+	cur, err := os.ReadFile(f.Path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	return unifiedDiff(f.Path, f.Path+" (generated)", withVersion(string(cur), f.Content), string(f.Content)), nil
+}
+
+// header matches the first line of a generated file, the gomock version is submatch 1.
+var header = regexp.MustCompile(`^// Code generated by gomock (\S+)\. DO NOT EDIT\.\n`)
+
+// withVersion returns cur with the gomock version of its header replaced by the one of generated,
+// so files generated by other builds of gomock, e.g. VCS stamped ones, aren't reported as stale.
+func withVersion(cur string, generated []byte) string {
+	m := header.FindSubmatch(generated)
+	if m == nil {
+		return cur
+	}
+	return header.ReplaceAllLiteralString(cur, string(m[0]))
+}
+
+// checkFiles checks files and prints the diffs of stale ones.
+// It fails if any file is stale.
+func checkFiles(files []gen.File) error {
+	stale := 0
+	for _, f := range files {
+		diff, err := checkFile(f)
+		if err != nil {
+			return err
+		}
+		if diff != "" {
+			fmt.Print(diff)
+			stale++
+		}
+	}
+
+	if stale > 0 {
+		return fmt.Errorf("%d of %d mock files are out of date", stale, len(files))
+	}
+	return nil
+}
+
+// mockable returns the interfaces of ifaces placed by gen.Place which can be mocked
+// in their output packages. The other ones, e.g. having unexported methods, are skipped.
+func mockable(ifaces []gen.Interface) []gen.Interface {
+	var res []gen.Interface
+	for _, i := range ifaces {
+		if err := i.Mockable(); err != nil {
+			if buildOpts.Logf != nil {
+				buildOpts.Logf("skipped %v", err)
+			}
+			continue
+		}
+		res = append(res, i)
+	}
+	return res
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/unkeep/gomock/gen"
+)
+
+func TestIfaceFilter(t *testing.T) {
+	cases := []struct {
+		name             string
+		include, exclude string
+		want             []string
+	}{
+		{name: "all", want: []string{"Internal", "Reader", "Writer"}},
+		{name: "include", include: "er$", want: []string{"Reader", "Writer"}},
+		{name: "exclude", exclude: "^Int", want: []string{"Reader", "Writer"}},
+		{name: "include_exclude", include: "er$", exclude: "^W", want: []string{"Reader"}},
+	}
+
+	ifaces, err := new(gen.Loader).LoadPackages("./testdata/all")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f, err := newIfaceFilter(c.include, c.exclude)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ifaceNames(f.filter(ifaces)); !reflect.DeepEqual(got, c.want) {
+				t.Fatalf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+
+	if _, err := newIfaceFilter("(", ""); err == nil {
+		t.Fatal("invalid -include is accepted")
+	}
+}
+
+func TestAll(t *testing.T) {
+	ifaces, err := new(gen.Loader).LoadPackages("./testdata/all")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name  string
+		opts  gen.Options
+		files map[string][]string // interfaces by file name
+	}{
+		{
+			name:  "package_dir",
+			files: map[string][]string{"mock_all_test.go": {"Internal", "Reader", "Writer"}},
+		},
+		{
+			name:  "other_dir",
+			opts:  gen.Options{Dir: t.TempDir()},
+			files: map[string][]string{"mock_all_test.go": {"Reader", "Writer"}},
+		},
+		{
+			name:  "out",
+			opts:  gen.Options{Out: filepath.Join(t.TempDir(), "mocks.go")},
+			files: map[string][]string{"mocks.go": {"Reader", "Writer"}},
+		},
+		{
+			name: "per_iface",
+			opts: gen.Options{Dir: t.TempDir(), PerInterface: true},
+			files: map[string][]string{
+				"mock_reader_test.go": {"Reader"},
+				"mock_writer_test.go": {"Writer"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			placed, err := gen.Place(ifaces, c.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			files, err := gen.Generate(mockable(placed), c.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := map[string][]string{}
+			for _, f := range files {
+				got[filepath.Base(f.Path)] = ifaceNames(f.Interfaces)
+			}
+			if !reflect.DeepEqual(got, c.files) {
+				t.Fatalf("got %v, want %v", got, c.files)
+			}
+		})
+	}
+}
+
+func TestCheckFile(t *testing.T) {
+	const body = "\n\npackage p\n"
+	path := filepath.Join(t.TempDir(), "mock_test.go")
+	generated := gen.File{Path: path, Content: []byte("// Code generated by gomock v1.2.0. DO NOT EDIT." + body)}
+
+	cases := []struct {
+		name  string
+		cur   string
+		stale bool
+	}{
+		{name: "same", cur: "// Code generated by gomock v1.2.0. DO NOT EDIT." + body},
+		{name: "other_version", cur: "// Code generated by gomock v0.0.0-20240101000000-0123456789ab+dirty. DO NOT EDIT." + body},
+		{name: "devel", cur: "// Code generated by gomock devel. DO NOT EDIT." + body},
+		{name: "other_body", cur: "// Code generated by gomock devel. DO NOT EDIT.\n\npackage q\n", stale: true},
+		{name: "no_header", cur: "package p\n", stale: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := os.WriteFile(path, []byte(c.cur), 0644); err != nil {
+				t.Fatal(err)
+			}
+			diff, err := checkFile(generated)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stale := diff != ""; stale != c.stale {
+				t.Fatalf("stale = %v, want %v, diff:\n%s", stale, c.stale, diff)
+			}
+		})
+	}
+}
+
+func ifaceNames(ifaces []gen.Interface) []string {
+	var names []string
+	for _, i := range ifaces {
+		names = append(names, i.Name)
+	}
+	return names
+}
@@ -0,0 +1,97 @@
+package gen
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+)
+
+// annotation marks an interface declaration to generate a mock for:
+//
+//	//gomock:generate name=fakeStorage out=storage_mock_test.go
+//	type Storage interface { ... }
+//
+// All the options are optional:
+// name is the mock type name, out is the output file relative to the package directory
+// and package is the package name of the output file.
+const annotation = "//gomock:generate"
+
+// annotated returns the interfaces of p annotated with //gomock:generate.
+func (l *Loader) annotated(p loadedPackage) ([]Interface, error) {
+	var ifaces []Interface
+	var err error
+	typeSpecs(p, func(spec *ast.TypeSpec, doc *ast.CommentGroup) bool {
+		var i Interface
+		var ok bool
+		i, ok, err = l.annotatedInterface(p, spec, doc)
+		if ok {
+			ifaces = append(ifaces, i)
+		}
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ifaces, nil
+}
+
+// annotatedInterface returns the interface declared by spec with the mock options of its annotation.
+// It returns false if the declaration isn't annotated.
+func (l *Loader) annotatedInterface(p loadedPackage, spec *ast.TypeSpec, doc *ast.CommentGroup) (Interface, bool, error) {
+	if doc == nil {
+		return Interface{}, false, nil
+	}
+
+	var c *ast.Comment
+	for _, cmt := range doc.List {
+		if cmt.Text == annotation || strings.HasPrefix(cmt.Text, annotation+" ") {
+			c = cmt
+			break
+		}
+	}
+	if c == nil {
+		return Interface{}, false, nil
+	}
+
+	pos := p.Fset.Position(c.Pos())
+	opts := map[string]string{}
+	for _, opt := range strings.Fields(strings.TrimPrefix(c.Text, annotation)) {
+		key, val, ok := strings.Cut(opt, "=")
+		if !ok || val == "" {
+			return Interface{}, false, errorAt(pos, "invalid option %q, want key=value", opt)
+		}
+		switch key {
+		case "name":
+			if !token.IsIdentifier(val) {
+				return Interface{}, false, errorAt(pos, "invalid mock name %q", val)
+			}
+		case "out", "package":
+		default:
+			return Interface{}, false, errorAt(pos, "unknown option %q", key)
+		}
+		opts[key] = val
+	}
+
+	tn, ok := p.Types.Scope().Lookup(spec.Name.Name).(*types.TypeName)
+	if !ok {
+		return Interface{}, false, errorAt(pos, "type %s not found", spec.Name.Name)
+	}
+	iface, err := newInterface(p, spec.Name.Name, types.Unalias(tn.Type()), true)
+	if err != nil {
+		return Interface{}, false, err
+	}
+	l.logf("found annotation of %s at %s", iface.Name, pos)
+
+	out := opts["out"]
+	if out == "" {
+		out = "mock_" + strings.ToLower(iface.Name) + "_test.go"
+	}
+	if !filepath.IsAbs(out) {
+		out = filepath.Join(p.Dir, out)
+	}
+
+	iface.Mock, iface.Out, iface.Package = opts["name"], out, opts["package"]
+	return iface, true, nil
+}
@@ -0,0 +1,77 @@
+package gen
+
+import (
+	"go/types"
+	"sort"
+)
+
+// Description describes the method set of an interface for tools like code generators,
+// e.g. encoded as JSON by "gomock describe".
+type Description struct {
+	Name       string              `json:"name"`
+	PkgPath    string              `json:"pkgPath,omitempty"` // empty for the built-in error interface
+	Type       string              `json:"type"`              // interface type, e.g. "storage.Repo[T]"
+	Pos        string              `json:"pos,omitempty"`     // "file:line:col" of the declaration
+	Doc        string              `json:"doc,omitempty"`
+	TypeParams []Param             `json:"typeParams,omitempty"` // type parameters of a generic interface, Type is the constraint
+	Methods    []MethodDescription `json:"methods"`
+	// Func is set for a function type, Methods is its signature named "Fn".
+	Func bool `json:"func,omitempty"`
+	// Imports are the packages of the types qualified by their Name, which is always set.
+	Imports []Import `json:"imports"`
+}
+
+// MethodDescription describes a method of an interface.
+// Unnamed parameters and results have no names.
+type MethodDescription struct {
+	Func
+	Pos string `json:"pos,omitempty"` // "file:line:col" of the declaration
+}
+
+// Describe describes the interface i. Types are qualified by the names of
+// the packages declaring them, which are renamed if they collide.
+func Describe(i Interface) Description {
+	imps := newImportSet("")
+	d := Description{
+		Name:    i.Name,
+		PkgPath: i.PkgPath(),
+		Type:    i.expr(imps.qualifier),
+		Doc:     i.doc(),
+		Methods: []MethodDescription{},
+		Func:    i.sig != nil,
+	}
+	if pos := i.Pos(); pos.IsValid() {
+		d.Pos = pos.String()
+	}
+
+	tparams := i.typeParams()
+	for j := 0; j < tparams.Len(); j++ {
+		tp := tparams.At(j)
+		d.TypeParams = append(d.TypeParams, Param{Name: tp.Obj().Name(), Type: types.TypeString(tp.Constraint(), imps.qualifier)})
+	}
+
+	for j, fn := range i.funcs(imps.qualifier) {
+		md := MethodDescription{Func: fn}
+		if md.Params == nil {
+			md.Params = []Param{}
+		}
+		if md.Res == nil {
+			md.Res = []Param{}
+		}
+		if d.Func {
+			md.Pos = d.Pos
+		} else if i.pkg.Package != nil {
+			if pos := i.pkg.Fset.Position(i.iface.Method(j).Pos()); pos.IsValid() {
+				md.Pos = pos.String()
+			}
+		}
+		d.Methods = append(d.Methods, md)
+	}
+
+	d.Imports = []Import{}
+	for path, name := range imps.names {
+		d.Imports = append(d.Imports, Import{Name: name, Path: path})
+	}
+	sort.Slice(d.Imports, func(a, b int) bool { return d.Imports[a].Path < d.Imports[b].Path })
+	return d
+}
@@ -0,0 +1,147 @@
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/packages"
+)
+
+// Diagnostic is an error at a position in the source code.
+type Diagnostic struct {
+	Pos string // "file:line:col"; empty if unknown
+	Msg string
+}
+
+func (d Diagnostic) Error() string {
+	if d.Pos == "" || d.Pos == "-" {
+		return d.Msg
+	}
+	return d.Pos + ": " + d.Msg
+}
+
+// errorAt returns a diagnostic at pos.
+func errorAt(pos token.Position, format string, args ...interface{}) Diagnostic {
+	d := Diagnostic{Msg: fmt.Sprintf(format, args...)}
+	if pos.IsValid() {
+		d.Pos = pos.String()
+	}
+	return d
+}
+
+// Diagnostics are errors reported together, one per line.
+type Diagnostics []Diagnostic
+
+func (ds Diagnostics) Error() string {
+	lines := make([]string, len(ds))
+	for i, d := range ds {
+		lines[i] = d.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// pkgErrors returns the errors preventing pkg from being inspected.
+// Type errors are only logged: they may be caused by stale mocks in the package,
+// which are going to be regenerated. Interfaces with invalid types are rejected by newInterface
+// with the type errors of their methods.
+func (l *Loader) pkgErrors(pkg *packages.Package) Diagnostics {
+	var ds Diagnostics
+	for _, err := range pkg.Errors {
+		if err.Kind == packages.TypeError {
+			l.logf("  warning: %s: %s", err.Pos, err.Msg)
+			continue
+		}
+		ds = append(ds, Diagnostic{Pos: err.Pos, Msg: err.Msg})
+	}
+	return ds
+}
+
+// typeErrorsIn returns the type errors of p positioned in the declaration of the method
+// or the field at pos, e.g. "bad.go:4:8: undefined: Undefined".
+func typeErrorsIn(p loadedPackage, pos token.Pos) []string {
+	start, end := pos, pos
+	for _, f := range p.Syntax {
+		if pos < f.Pos() || pos >= f.End() {
+			continue
+		}
+		path, _ := astutil.PathEnclosingInterval(f, pos, pos)
+		for _, n := range path {
+			if field, ok := n.(*ast.Field); ok {
+				start, end = field.Pos(), field.End()
+				break
+			}
+		}
+	}
+
+	var errs []string
+	for _, err := range p.TypeErrors {
+		if err.Fset == p.Fset && err.Pos >= start && err.Pos <= end {
+			errs = append(errs, err.Error())
+		}
+	}
+	return errs
+}
+
+// hasInvalid reports whether t refers to types which failed to type-check.
+func hasInvalid(t types.Type) bool {
+	switch t := t.(type) {
+	case *types.Basic:
+		return t.Kind() == types.Invalid
+	case *types.Pointer:
+		return hasInvalid(t.Elem())
+	case *types.Slice:
+		return hasInvalid(t.Elem())
+	case *types.Array:
+		return hasInvalid(t.Elem())
+	case *types.Chan:
+		return hasInvalid(t.Elem())
+	case *types.Map:
+		return hasInvalid(t.Key()) || hasInvalid(t.Elem())
+	case *types.Signature:
+		return hasInvalid(t.Params()) || hasInvalid(t.Results())
+	case *types.Tuple:
+		for i := 0; i < t.Len(); i++ {
+			if hasInvalid(t.At(i).Type()) {
+				return true
+			}
+		}
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			if hasInvalid(t.Field(i).Type()) {
+				return true
+			}
+		}
+	case *types.Named:
+		for i := 0; i < t.TypeArgs().Len(); i++ {
+			if hasInvalid(t.TypeArgs().At(i)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// logf logs a message if logging is enabled.
+func (l *Loader) logf(format string, args ...interface{}) {
+	if l.Logf != nil {
+		l.Logf(format, args...)
+	}
+}
+
+// logPkg logs the files of a loaded package.
+func (l *Loader) logPkg(pkg *packages.Package) {
+	if l.Logf == nil {
+		return
+	}
+	l.logf("loaded package %s (%s)", pkg.PkgPath, pkg.ID)
+	for _, f := range pkg.GoFiles {
+		l.logf("  scanned %s", f)
+	}
+	for _, f := range pkg.IgnoredFiles {
+		l.logf("  ignored %s (build constraints)", f)
+	}
+}
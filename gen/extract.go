@@ -0,0 +1,241 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"text/template"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/imports"
+)
+
+// Extraction describes an interface extracted from the method set of a type by Loader.Extract.
+type Extraction struct {
+	// Type is the type to extract the interface from, e.g. "github.com/someone/client.Client"
+	// or "client.Cache[string]" for an instantiation of a generic type.
+	Type string
+	// Name is the interface name. It defaults to the type name suffixed by "API", e.g. "ClientAPI".
+	Name string
+	// Methods are the names of the methods of the interface.
+	// They default to all the exported methods of the type.
+	Methods []string
+
+	// Out is the file to declare the interface in.
+	// The file needn't exist, its directory determines the package.
+	Out string
+	// Package is the package name of the file.
+	// It defaults to the package of its directory.
+	Package string
+}
+
+// Extract declares the interface described by e: it loads the type and returns
+// the file declaring the interface with the exported methods of the type,
+// including the ones with pointer receivers, and the interface to generate a mock for.
+// Doc comments of the methods are copied to the interface.
+func (l *Loader) Extract(e Extraction) (Interface, File, error) {
+	p, id, typ, err := l.loadType(e.Type)
+	if err != nil {
+		return Interface{}, File{}, err
+	}
+	var pos token.Position
+	if obj := p.Types.Scope().Lookup(id); obj != nil {
+		pos = p.Fset.Position(obj.Pos())
+	}
+	if named, ok := typ.(*types.Named); ok && named.TypeParams().Len() > named.TypeArgs().Len() {
+		return Interface{}, File{}, errorAt(pos, "%s is generic, its type arguments are required, e.g. %s[int]", id, e.Type)
+	}
+
+	name := e.Name
+	if name == "" {
+		name = id + "API"
+	}
+	if !token.IsIdentifier(name) {
+		return Interface{}, File{}, fmt.Errorf("invalid interface name %q", name)
+	}
+
+	out, err := filepath.Abs(e.Out)
+	if err != nil {
+		return Interface{}, File{}, err
+	}
+	pkgName, pkgPath := outputPkg(filepath.Dir(out))
+	if e.Package != "" && e.Package != pkgName {
+		// A package other than the one of the directory isn't importable.
+		pkgName, pkgPath = e.Package, ""
+	}
+	if pkgName == "" {
+		return Interface{}, File{}, fmt.Errorf("%s: no package in the directory, the package name is required", out)
+	}
+	declPkg := types.NewPackage(pkgPath, pkgName)
+
+	methods, err := extractMethods(typ, e.Methods, declPkg)
+	if err != nil {
+		return Interface{}, File{}, errorAt(pos, "%s: %v", id, err)
+	}
+
+	iface := types.NewInterfaceType(methods, nil).Complete()
+	tn := types.NewTypeName(token.NoPos, declPkg, name, nil)
+	named := types.NewNamed(tn, iface, nil)
+	declPkg.Scope().Insert(tn)
+
+	i := Interface{
+		Name: name,
+		pkg: loadedPackage{
+			Package: &packages.Package{Name: pkgName, PkgPath: pkgPath, Fset: p.Fset, Types: declPkg},
+			Dir:     filepath.Dir(out),
+		},
+		typ:     named,
+		iface:   iface,
+		outPath: pkgPath,
+	}
+
+	source := p.PkgPath + "." + id
+	if named, ok := typ.(*types.Named); ok && named.TypeArgs().Len() > 0 {
+		source = types.TypeString(typ, nil)
+	}
+	content, err := genDecl(i, source)
+	if err != nil {
+		return Interface{}, File{}, fmt.Errorf("%s: %v", out, err)
+	}
+	return i, File{Path: out, Package: pkgName, Interfaces: []Interface{i}, Content: content}, nil
+}
+
+// extractMethods returns the methods named names, all the exported ones if names is empty,
+// of the method set of typ and, unless it is an interface, of *typ.
+// The methods are declared in pkg, so they mustn't refer to unexported types of other packages.
+func extractMethods(typ types.Type, names []string, pkg *types.Package) ([]*types.Func, error) {
+	recv := typ
+	if _, ok := typ.Underlying().(*types.Interface); !ok {
+		if _, ok := typ.(*types.Pointer); !ok {
+			recv = types.NewPointer(typ)
+		}
+	}
+
+	selected := map[string]bool{}
+	for _, name := range names {
+		selected[name] = true
+	}
+
+	var methods []*types.Func
+	mset := types.NewMethodSet(recv)
+	for j := 0; j < mset.Len(); j++ {
+		m := mset.At(j).Obj().(*types.Func)
+		if !m.Exported() || len(names) > 0 && !selected[m.Name()] {
+			continue
+		}
+		delete(selected, m.Name())
+
+		sig := mset.At(j).Type().(*types.Signature)
+		if tn := unexportedType(sig, pkg); tn != nil {
+			return nil, fmt.Errorf("method %s refers to unexported type %s.%s", m.Name(), tn.Pkg().Name(), tn.Name())
+		}
+		sig = types.NewSignatureType(nil, nil, nil, sig.Params(), sig.Results(), sig.Variadic())
+		methods = append(methods, types.NewFunc(m.Pos(), pkg, m.Name(), sig))
+	}
+
+	for _, name := range names {
+		if selected[name] {
+			return nil, fmt.Errorf("no exported method %s", name)
+		}
+	}
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("no exported methods")
+	}
+	return methods, nil
+}
+
+// unexportedType returns an unexported named type of a package other than pkg t refers to, if any.
+func unexportedType(t types.Type, pkg *types.Package) *types.TypeName {
+	switch t := t.(type) {
+	case *types.Pointer:
+		return unexportedType(t.Elem(), pkg)
+	case *types.Slice:
+		return unexportedType(t.Elem(), pkg)
+	case *types.Array:
+		return unexportedType(t.Elem(), pkg)
+	case *types.Chan:
+		return unexportedType(t.Elem(), pkg)
+	case *types.Map:
+		if tn := unexportedType(t.Key(), pkg); tn != nil {
+			return tn
+		}
+		return unexportedType(t.Elem(), pkg)
+	case *types.Signature:
+		if tn := unexportedType(t.Params(), pkg); tn != nil {
+			return tn
+		}
+		return unexportedType(t.Results(), pkg)
+	case *types.Tuple:
+		for i := 0; i < t.Len(); i++ {
+			if tn := unexportedType(t.At(i).Type(), pkg); tn != nil {
+				return tn
+			}
+		}
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			if tn := unexportedType(t.Field(i).Type(), pkg); tn != nil {
+				return tn
+			}
+		}
+	case *types.Named:
+		if obj := t.Obj(); obj.Pkg() != nil && !obj.Exported() && obj.Pkg().Path() != pkg.Path() {
+			return obj
+		}
+		for i := 0; i < t.TypeArgs().Len(); i++ {
+			if tn := unexportedType(t.TypeArgs().At(i), pkg); tn != nil {
+				return tn
+			}
+		}
+	}
+	return nil
+}
+
+// declData is the data of declTmpl.
+type declData struct {
+	Version string
+	Package string
+	Imports []Import
+	Source  string // fully qualified type the interface is extracted from
+	Name    string
+	Methods []Func
+}
+
+var declTmpl = template.Must(template.New("decl").Funcs(tmplFuncs).Parse(`// Code generated by gomock {{.Version}}. DO NOT EDIT.
+// Extracted from:
+//	{{.Source}}
+
+package {{.Package}}
+
+import (
+{{range .Imports}}	{{.Name}} "{{.Path}}"
+{{end}})
+
+// {{.Name}} is the interface of {{.Source}}.
+type {{.Name}} interface {
+{{range .Methods}}{{with .Doc}}{{comment .}}
+{{end}}	{{.Name}}({{range .Params}}{{.Name}} {{.Type}}, {{end}}) ({{range .Res}}{{.Name}} {{.Type}}, {{end}})
+{{end}}}
+`))
+
+// genDecl generates the file declaring the extracted interface i.
+func genDecl(i Interface, source string) ([]byte, error) {
+	imps := newImportSet(i.outPath)
+	imps.reserve(i.Name)
+
+	data := declData{
+		Version: Version,
+		Package: i.pkg.Name,
+		Source:  source,
+		Name:    i.Name,
+		Methods: i.funcs(imps.qualifier),
+	}
+	data.Imports = imps.sorted()
+
+	var buf bytes.Buffer
+	if err := declTmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return imports.Process("", buf.Bytes(), &imports.Options{Comments: true, FormatOnly: true})
+}
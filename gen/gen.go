@@ -0,0 +1,384 @@
+// Package gen generates mocks of interfaces for the github.com/unkeep/gomock/mock package.
+//
+// Interfaces are loaded by a Loader and the mocks are generated by Generate:
+//
+//	ifaces, err := gen.Load("io.ReadCloser")
+//	if err != nil {
+//		return err
+//	}
+//	files, err := gen.Generate(ifaces, gen.Options{Out: "mocks/io.go"})
+//	if err != nil {
+//		return err
+//	}
+//	for _, f := range files {
+//		err = os.WriteFile(f.Path, f.Content, 0644)
+//		...
+//	}
+package gen
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strings"
+)
+
+// Version is the gomock version recorded in generated files: the version of
+// the github.com/unkeep/gomock module the binary is built with, "devel" if it is unknown.
+var Version = moduleVersion()
+
+func moduleVersion() string {
+	const module = "github.com/unkeep/gomock"
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "devel"
+	}
+	version := ""
+	if info.Main.Path == module {
+		version = info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == module {
+			version = dep.Version
+			if dep.Replace != nil {
+				version = dep.Replace.Version
+			}
+		}
+	}
+	if version == "" || version == "(devel)" {
+		return "devel"
+	}
+	return version
+}
+
+// Interface is a loaded interface or named function type to generate a mock for.
+// The mock of a function type has a method Fn returning a function
+// of the type which dispatches its calls to the mock.
+//
+// Mock, Out and Package describe the mock. Unset ones are set by Place
+// according to Options; the ones set by the caller or by a //gomock:generate
+// annotation are kept.
+type Interface struct {
+	Name string // interface name, e.g. "Reader"
+
+	Mock    string // mock type name, e.g. "mockReader"
+	Out     string // path of the file to generate the mock into
+	Package string // package name of the file
+
+	pkg     loadedPackage // zero for the built-in error interface
+	typ     types.Type
+	iface   *types.Interface // nil for a function type
+	sig     *types.Signature // set for a function type
+	inline  *inlineIface     // set for an interface declared inline
+	outPath string           // import path of the file package; empty if it isn't importable
+}
+
+// PkgPath returns the import path of the interface package,
+// empty for the built-in error interface.
+func (i Interface) PkgPath() string {
+	if i.pkg.Package == nil {
+		return ""
+	}
+	return i.pkg.PkgPath
+}
+
+// xtest reports whether the interface is declared by an external test package, e.g. "storage_test".
+func (i Interface) xtest() bool {
+	return i.pkg.Package != nil && strings.HasSuffix(i.pkg.Name, "_test") && strings.HasSuffix(i.pkg.PkgPath, "_test")
+}
+
+// String returns the fully qualified interface, e.g. "github.com/someone/storage.Storage",
+// "github.com/someone/storage.Repo[github.com/someone/storage.User]" or
+// "github.com/someone/storage.Service.store" for an inline interface.
+func (i Interface) String() string {
+	if i.inline != nil {
+		return i.PkgPath() + "." + i.inline.path
+	}
+	if named, ok := i.typ.(*types.Named); ok && named.TypeArgs().Len() > 0 {
+		return types.TypeString(i.typ, nil)
+	}
+	if path := i.PkgPath(); path != "" {
+		return path + "." + i.Name
+	}
+	return i.Name
+}
+
+// Type returns the interface type, *types.Named unless it is the built-in error interface
+// or an inline interface.
+func (i Interface) Type() types.Type {
+	return i.typ
+}
+
+// Pos returns the position of the interface declaration.
+func (i Interface) Pos() token.Position {
+	if i.pkg.Package == nil {
+		return token.Position{}
+	}
+	if i.inline != nil {
+		return i.pkg.Fset.Position(i.inline.pos)
+	}
+	if obj := i.pkg.Types.Scope().Lookup(i.Name); obj != nil {
+		return i.pkg.Fset.Position(obj.Pos())
+	}
+	return token.Position{}
+}
+
+// Loader loads interfaces using the go command, so modules, vendor directories,
+// workspaces and replace directives are respected.
+// Its fields must not be changed after the first load.
+type Loader struct {
+	Dir    string // directory to resolve interfaces and patterns in; defaults to the working directory
+	Tags   string // comma-separated build tags
+	GOOS   string // target operating system; defaults to GOOS
+	GOARCH string // target architecture; defaults to GOARCH
+	Tests  bool   // include _test.go files and external test packages
+
+	// Logf explains how interfaces are resolved: import paths,
+	// directories and files scanned. Nil disables logging.
+	Logf func(format string, args ...interface{})
+
+	pkgs map[string]loadedPackage // by import path
+}
+
+// Load loads the interfaces matching pattern relative to the working directory.
+// See Loader.Load.
+func Load(pattern string) ([]Interface, error) {
+	return new(Loader).Load(pattern)
+}
+
+// Load loads the interfaces matching pattern.
+// If pattern is a package pattern (see Loader.IsPackagePattern), Load returns
+// every exported interface of the packages which can be mocked.
+// Otherwise the pattern is an interface, e.g. "io.Reader",
+// "github.com/someone/pkg.Storage" or "pkg.Repo[pkg.User]" for an instantiation
+// of a generic interface. An interface declared inline, e.g. by a struct field,
+// is given by its path, e.g. "pkg.Service.store", or its position, "service.go:12[:col]";
+// its mock is named after the path, e.g. "mockServiceStore".
+func (l *Loader) Load(pattern string) ([]Interface, error) {
+	if l.IsPackagePattern(pattern) {
+		return l.LoadPackages(pattern)
+	}
+	i, err := l.loadInterface(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return []Interface{i}, nil
+}
+
+// LoadPackages returns every exported interface of the packages matching patterns
+// which can be mocked, ordered by package and name.
+func (l *Loader) LoadPackages(patterns ...string) ([]Interface, error) {
+	pkgs, err := l.loadPkgs(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	var ifaces []Interface
+	for _, p := range pkgs {
+		ifaces = append(ifaces, l.pkgInterfaces(p)...)
+	}
+	return ifaces, nil
+}
+
+// LoadAnnotated returns the interfaces of the packages matching patterns
+// annotated with //gomock:generate. The annotation options set Mock, Out
+// and Package of the interfaces:
+//
+//	//gomock:generate name=fakeStorage out=storage_mock_test.go package=storage
+//	type Storage interface { ... }
+//
+// Out is relative to the package directory and defaults to
+// "mock_<iface>_test.go". All the options are optional.
+func (l *Loader) LoadAnnotated(patterns ...string) ([]Interface, error) {
+	pkgs, err := l.loadPkgs(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	var ifaces []Interface
+	for _, p := range pkgs {
+		annotated, err := l.annotated(p)
+		if err != nil {
+			return nil, err
+		}
+		ifaces = append(ifaces, annotated...)
+	}
+	return ifaces, nil
+}
+
+// IsPackagePattern reports whether arg is a package pattern relative to the working directory.
+// See Loader.IsPackagePattern.
+func IsPackagePattern(arg string) bool {
+	return new(Loader).IsPackagePattern(arg)
+}
+
+// IsPackagePattern reports whether arg is a package pattern rather than an interface:
+// a pattern with the "..." wildcard, e.g. "./..." or "example.com/pkg/...", or a relative
+// path of a directory, e.g. "." or "./pkg". Other arguments, e.g. "./pkg.Iface", are interfaces.
+func (l *Loader) IsPackagePattern(arg string) bool {
+	if arg == "..." || strings.HasSuffix(arg, "/...") {
+		return true
+	}
+	if arg != "." && arg != ".." && !strings.HasPrefix(arg, "./") && !strings.HasPrefix(arg, "../") {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(l.dir(), arg))
+	return err == nil && info.IsDir()
+}
+
+// Options describe where and how mocks are generated.
+type Options struct {
+	// Out is the file to generate every mock into. By default mocks are generated
+	// into "mock_<package>_test.go" next to their interfaces.
+	// The file needn't exist, its directory determines the package.
+	Out string
+	// Dir is the directory to generate files into instead of the interface package directories.
+	Dir string
+	// PerInterface generates a file "mock_<iface>_test.go" per interface.
+	PerInterface bool
+
+	// Package is the package name of the generated files.
+	// It defaults to the package of their directory.
+	Package string
+	// DefaultPackage is the package name of files in directories without a package.
+	// It defaults to "mocks".
+	DefaultPackage string
+
+	// NamePattern is the mock type name pattern, e.g. "Fake{{.Iface}}".
+	// It defaults to "mock{{.Iface}}". Iface of an instantiation of a generic interface
+	// is suffixed by the type arguments, e.g. "RepoUser" for "Repo[User]".
+	NamePattern string
+	// Exported exports mock type names, e.g. "mockReader" becomes "MockReader".
+	Exported bool
+
+	// Template renders the files instead of the default template, see ParseTemplate.
+	// It is used by Generate only.
+	Template *Template
+}
+
+// File is a generated file.
+type File struct {
+	Path       string
+	Package    string
+	Interfaces []Interface // mocked interfaces
+	Content    []byte
+}
+
+// Place sets the mock type names, output files and packages of ifaces
+// which aren't set yet according to opts.
+func Place(ifaces []Interface, opts Options) ([]Interface, error) {
+	namer, err := newMockNamer(opts.NamePattern, opts.Exported)
+	if err != nil {
+		return nil, err
+	}
+
+	out, dir := opts.Out, opts.Dir
+	for _, p := range []*string{&out, &dir} {
+		if *p != "" {
+			if *p, err = filepath.Abs(*p); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	type outPkg struct{ name, path string }
+	outPkgs := map[string]outPkg{} // by directory
+
+	res := make([]Interface, len(ifaces))
+	for j, i := range ifaces {
+		if i.Mock == "" {
+			if i.Mock, err = namer.name(i.mockedName()); err != nil {
+				return nil, err
+			}
+		}
+
+		if i.Out == "" {
+			i.Out = out
+		}
+		if i.Out == "" {
+			fileDir := dir
+			if fileDir == "" {
+				if fileDir = i.pkg.Dir; fileDir == "" {
+					fileDir, _ = os.Getwd()
+				}
+			}
+			name := "mock_" + strings.ToLower(i.mockedName()) + "_test.go"
+			if !opts.PerInterface && i.pkg.Package != nil {
+				name = "mock_" + i.pkg.Name + "_test.go"
+			}
+			i.Out = filepath.Join(fileDir, name)
+		}
+
+		fileDir := filepath.Dir(i.Out)
+		op, ok := outPkgs[fileDir]
+		if !ok {
+			op.name, op.path = outputPkg(fileDir)
+			if op.name == "" {
+				op.name = opts.DefaultPackage
+				if op.name == "" {
+					op.name = "mocks"
+				}
+			}
+			outPkgs[fileDir] = op
+		}
+
+		// A package other than the one of the directory isn't importable.
+		i.outPath = op.path
+		pkgName := op.name
+		if i.xtest() && fileDir == i.pkg.Dir && strings.HasSuffix(i.Out, "_test.go") {
+			// An external test package isn't importable either, its mocks are generated into it.
+			pkgName, i.outPath = i.pkg.Name, i.pkg.PkgPath
+		}
+		if i.Package == "" {
+			i.Package = opts.Package
+		}
+		if i.Package == "" {
+			i.Package = pkgName
+		} else if i.Package != pkgName {
+			i.outPath = ""
+		}
+
+		res[j] = i
+	}
+	return res, nil
+}
+
+// Generate generates the files with the mocks of ifaces placed by Place.
+// Mocks placed into the same file are generated in the order of ifaces,
+// the files are ordered by path.
+func Generate(ifaces []Interface, opts Options) ([]File, error) {
+	placed, err := Place(ifaces, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	byPath := map[string]*File{}
+	var paths []string
+	for _, i := range placed {
+		f, ok := byPath[i.Out]
+		if !ok {
+			f = &File{Path: i.Out, Package: i.Package}
+			byPath[i.Out] = f
+			paths = append(paths, i.Out)
+		}
+		if f.Package != i.Package {
+			return nil, fmt.Errorf("%s: conflicting package names %s and %s", f.Path, f.Package, i.Package)
+		}
+		f.Interfaces = append(f.Interfaces, i)
+	}
+
+	sort.Strings(paths)
+	files := make([]File, 0, len(paths))
+	for _, path := range paths {
+		f := byPath[path]
+		if f.Content, err = genFile(f.Path, f.Interfaces, f.Package, f.Interfaces[0].outPath, opts.Template); err != nil {
+			return nil, fmt.Errorf("%s: %v", f.Path, err)
+		}
+		files = append(files, *f)
+	}
+	return files, nil
+}
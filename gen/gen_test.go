@@ -0,0 +1,864 @@
+package gen
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// TestGolden generates mocks of the testdata packages, compares them to the golden files
+// and checks that they compile in the packages they are generated into.
+func TestGolden(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name      string
+		pkg       string   // package of the interfaces
+		outDir    string   // directory of the generated file package
+		annotated bool     // generate the annotated types rather than all interfaces
+		ifaces    []string // interfaces to generate rather than all interfaces of pkg
+		loader    Loader   // build options of the interfaces and of the generated file
+	}{
+		{name: "collide", pkg: "./testdata/collide", outDir: "testdata/collide"},
+		{name: "collide_mocks", pkg: "./testdata/collide", outDir: "testdata/collide/mocks"},
+		{name: "docs", pkg: "./testdata/docs", outDir: "testdata/docs"},
+		{name: "funcs", pkg: "./testdata/funcs", outDir: "testdata/funcs", annotated: true},
+		{name: "inline", pkg: "./testdata/inline", outDir: "testdata/inline",
+			ifaces: []string{
+				"github.com/unkeep/gomock/gen/testdata/inline.Service.deps.store",
+				"github.com/unkeep/gomock/gen/testdata/inline.Service.logs",
+				"github.com/unkeep/gomock/gen/testdata/inline.Service.Do.cache",
+				"inline.go:26", // relative to pkg
+			}},
+		{name: "generic", pkg: "./testdata/generic", outDir: "testdata/generic",
+			ifaces: []string{
+				"github.com/unkeep/gomock/gen/testdata/generic.Repo",
+				"github.com/unkeep/gomock/gen/testdata/generic.Repo[github.com/unkeep/gomock/gen/testdata/generic.User]",
+				"github.com/unkeep/gomock/gen/testdata/generic.Cache",
+				"github.com/unkeep/gomock/gen/testdata/generic.Cache[string, []byte]",
+			}},
+		{name: "xtest", pkg: "./testdata/xtest", outDir: "testdata/xtest",
+			ifaces: []string{
+				"github.com/unkeep/gomock/gen/testdata/xtest.Remote", // integration.go
+				"github.com/unkeep/gomock/gen/testdata/xtest.Store",  // store_test.go
+			},
+			loader: Loader{Tests: true, Tags: "integration"}},
+		{name: "xtest_external", pkg: "./testdata/xtest", outDir: "testdata/xtest",
+			ifaces: []string{
+				"github.com/unkeep/gomock/gen/testdata/xtest_test.Cache",
+				"github.com/unkeep/gomock/gen/testdata/xtest_test.Loader",
+			},
+			loader: Loader{Tests: true}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			l := c.loader
+			load := l.LoadPackages
+			if c.annotated {
+				load = l.LoadAnnotated
+			}
+			if c.ifaces != nil {
+				load = func(...string) ([]Interface, error) {
+					var ifaces []Interface
+					l.Dir = c.pkg
+					for _, name := range c.ifaces {
+						loaded, err := l.Load(name)
+						if err != nil {
+							return nil, err
+						}
+						ifaces = append(ifaces, loaded...)
+					}
+					return ifaces, nil
+				}
+			}
+			ifaces, err := load(c.pkg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Mocks of test files are generated into test files.
+			out := filepath.Join(wd, c.outDir, "mock_gen.go")
+			if c.loader.Tests {
+				out = filepath.Join(wd, c.outDir, "mock_gen_test.go")
+			}
+			opts := Options{Out: out}
+			placed, err := Place(ifaces, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Like -all, interfaces which can't be mocked in the output package are skipped.
+			ifaces = ifaces[:0]
+			for _, i := range placed {
+				if i.Mockable() == nil {
+					ifaces = append(ifaces, i)
+				}
+			}
+
+			files, err := Generate(ifaces, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(files) != 1 {
+				t.Fatalf("got %d files, want 1", len(files))
+			}
+			checkGolden(t, c.name, files[0].Content)
+			checkCompiles(t, c.loader, "./"+c.outDir, map[string][]byte{out: files[0].Content})
+		})
+	}
+}
+
+// checkGolden compares src to the golden file testdata/<name>.golden
+// or updates the golden file with -update.
+func checkGolden(t *testing.T, name string, src []byte) {
+	t.Helper()
+	golden := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(golden, src, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(src, want) {
+		t.Errorf("generated files differ from %s:\n%s", golden, src)
+	}
+}
+
+// checkCompiles checks that the package pkg with the generated files of overlay compiles.
+// Packages are loaded with the build options of l, including test files.
+func checkCompiles(t *testing.T, l Loader, pkg string, overlay map[string][]byte) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Dir, l.Tests = wd, true
+	cfg := l.config()
+	cfg.Overlay = overlay
+	pkgs, err := packages.Load(cfg, pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	packages.Visit(pkgs, nil, func(p *packages.Package) {
+		for _, err := range p.Errors {
+			t.Errorf("generated files don't compile: %v", err)
+		}
+	})
+}
+
+func TestExternalTestPackageOut(t *testing.T) {
+	l := &Loader{Tests: true}
+	ifaces, err := l.Load("github.com/unkeep/gomock/gen/testdata/xtest_test.Cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The external test package can't be imported by a file other than its test files.
+	for _, out := range []string{"testdata/xtest/mock_gen.go", "testdata/mock_gen_test.go"} {
+		_, err := Generate(ifaces, Options{Out: out})
+		if err == nil || !strings.Contains(err.Error(), "external test package github.com/unkeep/gomock/gen/testdata/xtest_test can't be imported") {
+			t.Errorf("%s: got error %v, want external test package can't be imported", out, err)
+		}
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		iface string
+		err   string // positions are relative to testdata
+	}{
+		{iface: "github.com/unkeep/gomock/gen/testdata/bad.Setter"},
+		{
+			iface: "github.com/unkeep/gomock/gen/testdata/bad.Getter",
+			err:   "bad/bad.go:6:2: method Get of Getter has invalid types: bad/bad.go:6:18: undefined: Undefined",
+		},
+		{
+			iface: "github.com/unkeep/gomock/gen/testdata/bad.Store",
+			err:   "bad/bad.go:6:2: method Get of Store has invalid types: bad/bad.go:6:18: undefined: Undefined",
+		},
+		{
+			iface: "github.com/unkeep/gomock/gen/testdata/syntax.Broken",
+			err:   "syntax/syntax.go:9:1: expected declaration, found Broken",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.iface[strings.LastIndex(c.iface, "/")+1:], func(t *testing.T) {
+			_, err := new(Loader).Load(c.iface)
+			if c.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("got no error, want %s", c.err)
+			}
+			if got := strings.ReplaceAll(err.Error(), filepath.Join(wd, "testdata")+string(filepath.Separator), ""); got != c.err {
+				t.Fatalf("got error %s, want %s", got, c.err)
+			}
+		})
+	}
+}
+
+func TestIsPackagePattern(t *testing.T) {
+	cases := map[string]bool{
+		"./...":                        true,
+		"example.com/pkg/...":          true,
+		".":                            true,
+		"./testdata/imports":           true,
+		"../gen/testdata/imports":      true,
+		"./testdata/imports.Renderer":  false,
+		"../testdata/imports.Renderer": false,
+		"./testdata/none":              false,
+		"io.Reader":                    false,
+		"./inline.go:13":               false,
+	}
+	for arg, want := range cases {
+		if got := IsPackagePattern(arg); got != want {
+			t.Errorf("IsPackagePattern(%q) = %v, want %v", arg, got, want)
+		}
+	}
+}
+
+func TestFindInterface(t *testing.T) {
+	cases := []struct {
+		iface string
+		path  string
+		id    string
+		err   string
+	}{
+		{iface: "io.Reader", path: "io", id: "Reader"},
+		{iface: "net/http.Handler", path: "net/http", id: "Handler"},
+		{iface: "gopkg.in/yaml.v3.Marshaler", path: "gopkg.in/yaml.v3", id: "Marshaler"},
+		{iface: "*io.Reader", err: "unrecognized interface *io.Reader: want <package>.<interface>"},
+		{iface: "Reader", err: "unrecognized interface: Reader"},
+		{iface: "net/http/", err: "interface name cannot end with a '/' character: net/http/"},
+		{iface: "io.Reader io.Writer", err: "couldn't parse interface: io.Reader io.Writer"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.iface, func(t *testing.T) {
+			path, id, err := new(Loader).findInterface(c.iface)
+			if c.err != "" {
+				if err == nil || err.Error() != c.err {
+					t.Fatalf("got error %v, want %s", err, c.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if path != c.path || id != c.id {
+				t.Fatalf("got %s.%s, want %s.%s", path, id, c.path, c.id)
+			}
+		})
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	tmpl, err := ParseTemplate("log.tmpl", `
+// {{.Mock}} mocks {{.IfacePkg}}.{{.Iface}} of {{.IfacePath}}.
+{{comment .Doc}}
+type {{.Mock}} struct {
+	{{.MockPkg}}.M
+}
+{{range .Methods}}
+{{comment .Doc}}
+func ({{$.Recv}} *{{$.Mock}}) {{.Name}}({{range .Params}}{{.Name}} {{.Type}}, {{end}}) ({{range .Res}}{{.Name}} {{.Type}}, {{end}}) {
+	{{import "log"}}.Println("{{.Name}}", {{.Variadic}}{{range .Params}}, {{.Variadic}}{{end}})
+	{{$.MockPkg}}.Call({{$.Recv}}, {{$.IfaceFull}}.{{.Name}}, {{range .Params}}{{.Name}}, {{end}}).Return({{range .Res}}&{{.Name}}, {{end}})
+	return
+}
+{{end}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ifaces, err := new(Loader).Load("./testdata/docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := Generate(ifaces, Options{Out: "mocks/mock_docs.go", Template: tmpl})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := string(files[0].Content)
+	for _, want := range []string{
+		`log1 "log"`, // the log parameter isn't shadowing the import
+		"// mockLogger mocks docs.Logger of github.com/unkeep/gomock/gen/testdata/docs.\n// Logger logs messages.\n//\n// It is safe for concurrent use.\ntype mockLogger",
+		"// Log logs the message formatted\n// by fmt.Sprintf.\nfunc (m *mockLogger) Log(",
+		"\n\nfunc (m *mockLogger) Level(",
+		"// Close flushes the messages\n// and closes the output.\nfunc (m *mockLogger) Close(", // doc comment of the embedded base.Closer method
+		`log1.Println("Log", true, false, true)`,
+		`log1.Println("Level", false)`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated file doesn't contain %q:\n%s", want, src)
+		}
+	}
+}
+
+func TestTemplateFuncType(t *testing.T) {
+	const mock = `
+// {{.Mock}} mocks{{if .FuncType}} function type{{end}} {{.IfaceFull}}.
+type {{.Mock}}{{.TypeParams}} struct {
+	{{.MockPkg}}.M
+}
+`
+	cases := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "mock", text: mock, want: "// mockClock mocks function type funcs.Clock.\n"},
+		{name: "func", text: mock + `{{define "func"}}
+// {{.Mock}} is a function.
+type {{.Mock}}{{.TypeParams}} struct{}
+{{end}}`, want: "// mockClock is a function.\n"},
+	}
+
+	ifaces, err := new(Loader).Load("github.com/unkeep/gomock/gen/testdata/funcs.Clock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tmpl, err := ParseTemplate(c.name+".tmpl", c.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			files, err := Generate(ifaces, Options{Out: "mocks/mock_funcs.go", Template: tmpl})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if src := string(files[0].Content); !strings.Contains(src, c.want) {
+				t.Errorf("generated file doesn't contain %q:\n%s", c.want, src)
+			}
+		})
+	}
+}
+
+func TestParseTemplateError(t *testing.T) {
+	_, err := ParseTemplate("log.tmpl", "{{if}}")
+	if want := "log.tmpl: mock:1: missing value for if"; err == nil || err.Error() != want {
+		t.Fatalf("got error %v, want %s", err, want)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	ifaces, err := new(Loader).Load("./testdata/docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := Describe(ifaces[0])
+
+	if d.Name != "Logger" || d.PkgPath != "github.com/unkeep/gomock/gen/testdata/docs" || d.Type != "docs.Logger" {
+		t.Errorf("got %s %s %s, want Logger of docs", d.Name, d.PkgPath, d.Type)
+	}
+	if want := "Logger logs messages.\n\nIt is safe for concurrent use."; d.Doc != want {
+		t.Errorf("got doc %q, want %q", d.Doc, want)
+	}
+	if !strings.HasSuffix(d.Pos, filepath.Join("testdata", "docs", "docs.go")+":11:6") {
+		t.Errorf("got position %s, want docs.go:11:6", d.Pos)
+	}
+
+	var names []string
+	for _, m := range d.Methods {
+		names = append(names, m.Name)
+	}
+	if got, want := strings.Join(names, " "), "Close Level Log"; got != want {
+		t.Fatalf("got methods %s, want %s", got, want)
+	}
+
+	log := d.Methods[2]
+	if !log.Variadic || len(log.Params) != 2 || !log.Params[1].Variadic || log.Params[1].Type != "...interface{}" {
+		t.Errorf("Log isn't described as variadic: %+v", log.Params)
+	}
+	if !strings.HasSuffix(log.Pos, "docs.go:14:2") {
+		t.Errorf("got Log position %s, want docs.go:14:2", log.Pos)
+	}
+	if len(log.Res) != 1 || log.Res[0].Name != "" || log.Res[0].Type != "error" {
+		t.Errorf("got Log results %+v, want an unnamed error", log.Res)
+	}
+	if want := "Close flushes the messages\nand closes the output."; d.Methods[0].Doc != want {
+		t.Errorf("got Close doc %q, want %q", d.Methods[0].Doc, want)
+	}
+
+	want := []Import{{Name: "docs", Path: "github.com/unkeep/gomock/gen/testdata/docs"}}
+	if len(d.Imports) != 1 || d.Imports[0] != want[0] {
+		t.Errorf("got imports %+v, want %+v", d.Imports, want)
+	}
+}
+
+func TestLoadFuncType(t *testing.T) {
+	ifaces, err := new(Loader).LoadPackages("./testdata/funcs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ifaces) != 0 {
+		t.Errorf("got %d interfaces of ./testdata/funcs, want function types to be skipped", len(ifaces))
+	}
+
+	ifaces, err = new(Loader).Load("github.com/unkeep/gomock/gen/testdata/funcs.Handler[int]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := Describe(ifaces[0])
+	if !d.Func || d.Type != "funcs.Handler[int]" || len(d.Methods) != 1 {
+		t.Fatalf("got %+v, want function type Handler[int]", d)
+	}
+	if fn := d.Methods[0]; fn.Name != "Fn" || !fn.Variadic || len(fn.Params) != 3 || fn.Params[1].Type != "int" || fn.Pos != d.Pos {
+		t.Errorf("got %+v, want the signature of Handler[int]", fn)
+	}
+
+	_, err = new(Loader).Load("time.Duration")
+	if want := "Duration is neither an interface nor a function type"; err == nil || !strings.HasSuffix(err.Error(), want) {
+		t.Errorf("got error %v, want %s", err, want)
+	}
+}
+
+func TestExtract(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(wd, "testdata", "extract", "api", "client.go")
+
+	iface, f, err := new(Loader).Extract(Extraction{
+		Type: "github.com/unkeep/gomock/gen/testdata/extract.Client",
+		Out:  out,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if iface.Name != "ClientAPI" || iface.String() != "github.com/unkeep/gomock/gen/testdata/extract/api.ClientAPI" {
+		t.Errorf("got interface %s, want api.ClientAPI", iface)
+	}
+
+	mocks, err := Generate([]Interface{iface}, Options{Out: filepath.Join(wd, "testdata", "extract", "api", "mock_test.go")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := append(append(f.Content, "\n// mock_test.go\n\n"...), mocks[0].Content...)
+	checkGolden(t, "extract", src)
+	checkCompiles(t, Loader{}, "./testdata/extract/api", map[string][]byte{f.Path: f.Content, mocks[0].Path: mocks[0].Content})
+
+	iface, _, err = new(Loader).Extract(Extraction{
+		Type:    "github.com/unkeep/gomock/gen/testdata/extract.Client",
+		Methods: []string{"Close", "Put"},
+		Out:     out,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, fn := range iface.funcs(nil) {
+		names = append(names, fn.Name)
+	}
+	if got, want := strings.Join(names, " "), "Close Put"; got != want {
+		t.Errorf("got methods %s, want %s", got, want)
+	}
+}
+
+func TestExtractErrors(t *testing.T) {
+	cases := []struct {
+		e   Extraction
+		err string
+	}{
+		{Extraction{Type: "github.com/unkeep/gomock/gen/testdata/extract.Client", Methods: []string{"Get", "reset"}},
+			"Client: no exported method reset"},
+		{Extraction{Type: "github.com/unkeep/gomock/gen/testdata/extract.Option"},
+			"Option: no exported methods"},
+		{Extraction{Type: "github.com/unkeep/gomock/gen/testdata/extract.Client", Name: "client-api"},
+			`invalid interface name "client-api"`},
+	}
+	for _, c := range cases {
+		t.Run(c.err, func(t *testing.T) {
+			c.e.Out = filepath.Join("testdata", "extract", "api", "client.go")
+			_, _, err := new(Loader).Extract(c.e)
+			if err == nil || !strings.HasSuffix(err.Error(), c.err) {
+				t.Errorf("got error %v, want %s", err, c.err)
+			}
+		})
+	}
+}
+
+func TestLoadInline(t *testing.T) {
+	const pkg = "github.com/unkeep/gomock/gen/testdata/inline."
+	l := &Loader{Dir: filepath.Join("testdata", "inline")}
+
+	ifaces, err := l.Load(pkg + "Service.deps.store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := Describe(ifaces[0])
+	if d.Name != "ServiceDepsStore" || d.Doc != "store stores the items." || !strings.HasSuffix(d.Pos, "inline.go:13:9") {
+		t.Errorf("got %s %q at %s, want ServiceDepsStore declared by the field store", d.Name, d.Doc, d.Pos)
+	}
+	if IsPackagePattern("./inline.go:13") {
+		t.Error("position of an inline interface is taken for a package pattern")
+	}
+
+	cases := []struct {
+		iface string
+		err   string
+	}{
+		{pkg + "Service.deps.cache", "Service.deps has no field, method or parameter cache"},
+		{pkg + "Service.deps", "Service.deps is not an inline interface"},
+		{pkg + "Handle.ctx", "Handle.ctx is not an inline interface"},
+		{"inline.go:9", "inline.go:9: no inline interface found"},
+	}
+	for _, c := range cases {
+		t.Run(c.iface, func(t *testing.T) {
+			_, err := l.Load(c.iface)
+			if err == nil || !strings.HasSuffix(err.Error(), c.err) {
+				t.Errorf("got error %v, want %s", err, c.err)
+			}
+		})
+	}
+}
+
+func TestPackageClause(t *testing.T) {
+	ifaces, err := new(Loader).Load("github.com/unkeep/gomock/gen/testdata/imports.Renderer")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		opts Options
+		pkg  string
+		self bool // the interface package is the package of the file
+	}{
+		{name: "package_dir", opts: Options{Out: "testdata/imports/mock_test.go"}, pkg: "imports", self: true},
+		{name: "other_package", opts: Options{Out: "testdata/imports/fakes/mock_test.go"}, pkg: "fakes"},
+		{name: "no_package", opts: Options{Out: "testdata/none/mock_test.go"}, pkg: "mocks"},
+		{name: "default_package", opts: Options{Out: "testdata/none/mock_test.go", DefaultPackage: "fakes"}, pkg: "fakes"},
+		{name: "package_option", opts: Options{Out: "testdata/imports/mock_test.go", Package: "imports_test"}, pkg: "imports_test"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			files, err := Generate(ifaces, c.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			src := string(files[0].Content)
+
+			if !strings.HasPrefix(src, "// Code generated by gomock "+Version+". DO NOT EDIT.\n") {
+				t.Errorf("no generated code header:\n%s", src)
+			}
+			if files[0].Package != c.pkg || !strings.Contains(src, "\npackage "+c.pkg+"\n") {
+				t.Errorf("got package %s, want %s:\n%s", files[0].Package, c.pkg, src)
+			}
+			imported := strings.Contains(src, `"github.com/unkeep/gomock/gen/testdata/imports"`)
+			if imported == c.self {
+				t.Errorf("interface package imported: %v, want %v:\n%s", imported, !c.self, src)
+			}
+			if !strings.Contains(src, `"github.com/unkeep/gomock/mock"`) {
+				t.Errorf("mock package isn't imported:\n%s", src)
+			}
+		})
+	}
+}
+
+func TestMethodSets(t *testing.T) {
+	cases := []struct {
+		iface   string
+		methods int
+		want    []string
+	}{{
+		iface:   "github.com/unkeep/gomock/gen/testdata/imports.Renderer",
+		methods: 3,
+		want: []string{
+			"\t\"html/template\"\n",
+			"\ttemplate1 \"text/template\"\n",
+			"\tyaml \"gopkg.in/yaml.v3\"\n",
+			"func (m *mockRenderer) HTML(t *template.Template, w http.ResponseWriter) (out1 error) {\n",
+			"func (m *mockRenderer) Text(t *template1.Template, w io.Writer) (out1 error) {\n",
+			"func (m *mockRenderer) Node(n *yaml.Node) (out1 yaml.Kind, out2 error) {\n",
+		},
+	}, {
+		iface:   "github.com/unkeep/gomock/gen/testdata/methods.Body",
+		methods: 4,
+		want: []string{
+			"func (m *mockBody) Close() (out1 error) {\n",
+			"func (m *mockBody) Read(p []byte) (n int, err error) {\n",
+			"func (m *mockBody) Write(p []byte) (n int, err error) {\n",
+			"func (m *mockBody) WriteTo(w io.Writer) (out1 int64, out2 error) {\n",
+		},
+	}, {
+		iface:   "github.com/unkeep/gomock/gen/testdata/methods.Service",
+		methods: 2,
+		want: []string{
+			"func (m *mockService) Serve(w http.ResponseWriter, r *http.Request) (out1 error) {\n",
+			"func (m *mockService) ServeHTTP(in1 http.ResponseWriter, in2 *http.Request) {\n",
+		},
+	}}
+
+	for _, c := range cases {
+		t.Run(c.iface[strings.LastIndex(c.iface, "/")+1:], func(t *testing.T) {
+			ifaces, err := new(Loader).Load(c.iface)
+			if err != nil {
+				t.Fatal(err)
+			}
+			files, err := Generate(ifaces, Options{Out: "testdata/methods/mocks/mock_test.go"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			src := string(files[0].Content)
+			// The mock has EXPECT and ON besides the interface methods.
+			if n := strings.Count(src, "\nfunc (m *mock"+ifaces[0].Name+") ") - 2; n != c.methods {
+				t.Errorf("got %d methods, want %d:\n%s", n, c.methods, src)
+			}
+			for _, want := range c.want {
+				if !strings.Contains(src, want) {
+					t.Errorf("generated file doesn't contain %q:\n%s", want, src)
+				}
+			}
+		})
+	}
+}
+
+func TestMockNames(t *testing.T) {
+	ifaces, err := new(Loader).Load("github.com/unkeep/gomock/gen/testdata/imports.Renderer")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		opts Options
+		mock string // mock type name
+		ctor string // constructor name
+		err  string
+	}{
+		{name: "default", mock: "mockRenderer", ctor: "newMockRenderer"},
+		{name: "exported", opts: Options{Exported: true}, mock: "MockRenderer", ctor: "NewMockRenderer"},
+		{name: "pattern", opts: Options{NamePattern: "fake{{.Iface}}"}, mock: "fakeRenderer", ctor: "newFakeRenderer"},
+		{name: "exported_pattern", opts: Options{NamePattern: "fake{{.Iface}}", Exported: true}, mock: "FakeRenderer", ctor: "NewFakeRenderer"},
+		{name: "unknown_field", opts: Options{NamePattern: "{{.Name}}"}, err: "invalid mock name pattern"},
+		{name: "invalid_name", opts: Options{NamePattern: "mock-{{.Iface}}"}, err: `invalid mock name "mock-Renderer" of Renderer`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.opts.Out = "testdata/imports/fakes/mock_test.go"
+			files, err := Generate(ifaces, c.opts)
+			if c.err != "" {
+				if err == nil || !strings.Contains(err.Error(), c.err) {
+					t.Fatalf("got error %v, want %s", err, c.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			src := string(files[0].Content)
+			for _, want := range []string{
+				"\ntype " + c.mock + " struct {\n",
+				"\nfunc " + c.ctor + "(t mock.TestingT, opts ...mock.Option) *" + c.mock + " {\n",
+				"mock.New(t, append([]mock.Option{mock.CheckOnCleanup()}, opts...)...)",
+			} {
+				if !strings.Contains(src, want) {
+					t.Errorf("generated file doesn't contain %q:\n%s", want, src)
+				}
+			}
+		})
+	}
+}
+
+func TestMockNameCollisions(t *testing.T) {
+	ifaces, err := new(Loader).Load("github.com/unkeep/gomock/gen/testdata/imports.Renderer")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name   string
+		ifaces []Interface
+		opts   Options
+		err    string
+	}{
+		{
+			name:   "package",
+			ifaces: ifaces,
+			opts:   Options{Out: "testdata/imports/mock_test.go", NamePattern: "{{.Iface}}"},
+			err:    "Renderer: Renderer collides with a declaration of package imports, rename the mock",
+		},
+		{
+			name:   "mocks",
+			ifaces: append(ifaces, ifaces...),
+			opts:   Options{Out: "testdata/imports/fakes/mock_test.go"},
+			err:    "mockRenderer collides with the mock of github.com/unkeep/gomock/gen/testdata/imports.Renderer, rename the mock",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := Generate(c.ifaces, c.opts)
+			if err == nil || !strings.HasSuffix(err.Error(), c.err) {
+				t.Fatalf("got error %v, want %s", err, c.err)
+			}
+		})
+	}
+}
+
+func TestAssertions(t *testing.T) {
+	ifaces, err := new(Loader).LoadPackages("./testdata/generic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := filepath.Abs("testdata/generic/mock_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := Generate(ifaces, Options{Out: out})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := string(files[0].Content)
+	for _, want := range []string{
+		"\nvar _ Summer[int64] = (*mockSummer[int64])(nil)\n",
+		"\n// mockPrinter isn't asserted to implement Printer: no type arguments satisfying its constraints are found\n",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated file doesn't contain %q:\n%s", want, src)
+		}
+	}
+	if errs := typeErrors(t, "./testdata/generic", map[string][]byte{out: files[0].Content}); len(errs) > 0 {
+		t.Fatalf("generated file doesn't compile: %v\n%s", errs, src)
+	}
+}
+
+func TestStaleMockAssertion(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ifaces, err := new(Loader).Load("github.com/unkeep/gomock/gen/testdata/imports.Renderer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(wd, "testdata", "imports", "mock_gen.go")
+	files, err := Generate(ifaces, Options{Out: out})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A method added to the interface makes the mock stale.
+	src := filepath.Join(wd, "testdata", "imports", "imports.go")
+	iface, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale := strings.Replace(string(iface), "Renderer interface {\n", "Renderer interface {\n\tFlush() error\n", 1)
+
+	errs := typeErrors(t, "./testdata/imports", map[string][]byte{out: files[0].Content, src: []byte(stale)})
+	if len(errs) != 1 || !strings.HasPrefix(errs[0], out+":") || !strings.Contains(errs[0], "missing method Flush") {
+		t.Fatalf("got errors %v, want the mock assertion failing with missing method Flush", errs)
+	}
+}
+
+// typeErrors returns the errors of loading pkg with the overlay.
+func typeErrors(t *testing.T, pkg string, overlay map[string][]byte) []string {
+	t.Helper()
+	cfg := new(Loader).config()
+	cfg.Overlay = overlay
+	pkgs, err := packages.Load(cfg, pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var errs []string
+	packages.Visit(pkgs, nil, func(p *packages.Package) {
+		for _, err := range p.Errors {
+			errs = append(errs, err.Error())
+		}
+	})
+	return errs
+}
+
+func TestLoadModules(t *testing.T) {
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Workspaces reject -mod=mod.
+	t.Setenv("GOFLAGS", "")
+
+	// The app module requires the dep module replaced by a local directory
+	// and is in a workspace with the work module.
+	dir := t.TempDir()
+	write(filepath.Join(dir, "app", "go.mod"), "module example.com/app\n\ngo 1.22\n\nrequire example.com/dep v1.0.0\n\nreplace example.com/dep => ../dep\n")
+	write(filepath.Join(dir, "app", "app.go"), "package app\n\ntype Service interface{ Do() error }\n")
+	write(filepath.Join(dir, "app", "sub", "sub.go"), "package sub\n\ntype Handler interface{ Handle() }\n")
+	write(filepath.Join(dir, "dep", "go.mod"), "module example.com/dep\n\ngo 1.22\n")
+	write(filepath.Join(dir, "dep", "dep.go"), "package dep\n\ntype Store interface{ Get(key string) string }\n")
+	write(filepath.Join(dir, "work", "go.mod"), "module example.com/work\n\ngo 1.22\n")
+	write(filepath.Join(dir, "work", "work.go"), "package work\n\ntype Queue interface{ Push(v int) }\n")
+
+	cases := []struct {
+		name  string
+		dir   string // relative to the temporary directory
+		iface string
+		file  string // file declaring the interface relative to the temporary directory; empty for std
+		work  bool   // load in the workspace
+	}{
+		{name: "module", dir: "app", iface: "example.com/app.Service", file: "app/app.go"},
+		{name: "relative", dir: "app", iface: "./sub", file: "app/sub/sub.go"},
+		{name: "relative_iface", dir: "app", iface: "./sub.Handler", file: "app/sub/sub.go"},
+		{name: "replace", dir: "app", iface: "example.com/dep.Store", file: "dep/dep.go"},
+		{name: "workspace", dir: "app", iface: "example.com/work.Queue", file: "work/work.go", work: true},
+		{name: "std", dir: "app", iface: "io.Reader"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if c.work {
+				write(filepath.Join(dir, "go.work"), "go 1.22\n\nuse (\n\t./app\n\t./dep\n\t./work\n)\n")
+				defer os.Remove(filepath.Join(dir, "go.work"))
+			}
+			ifaces, err := (&Loader{Dir: filepath.Join(dir, c.dir)}).Load(c.iface)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ifaces) != 1 {
+				t.Fatalf("got %v, want %s", ifaces, c.iface)
+			}
+			if pos := ifaces[0].Pos(); !pos.IsValid() {
+				t.Fatalf("%s has no position", c.iface)
+			} else if c.file != "" && pos.Filename != filepath.Join(dir, c.file) {
+				t.Fatalf("got %s declared in %s, want %s", c.iface, pos.Filename, c.file)
+			}
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// inlineIface is an interface declared inline, e.g. by a struct field or a function parameter.
+type inlineIface struct {
+	path string    // path of the interface in its package, e.g. "Service.deps.store"
+	pos  token.Pos // position of the interface type
+	doc  string    // doc comment text of the field or parameter declaring the interface
+}
+
+// inlinePosRe matches the position of an inline interface, e.g. "service.go:12" or "service.go:12:8".
+var inlinePosRe = regexp.MustCompile(`^(.+\.go):(\d+)(?::(\d+))?$`)
+
+// loadInlinePath locates the inline interface at the path in a package, e.g. "pkg.Service.store"
+// for a struct field, "pkg.Handle.deps" for a function parameter or "pkg.Service.Do.deps"
+// for a parameter of a method. Struct fields are nested, e.g. "pkg.Service.deps.store".
+// found reports whether a prefix of the path is a declaration of a package.
+func (l *Loader) loadInlinePath(iface string) (i Interface, found bool, err error) {
+	elems := strings.Split(iface[strings.LastIndex(iface, "/")+1:], ".")
+	for n := 1; n < len(elems)-1; n++ {
+		prefix := iface[:len(iface)-len(strings.Join(elems[len(elems)-n:], "."))-1]
+		path, id, err := l.findInterface(prefix)
+		if err != nil {
+			continue
+		}
+		p, err := l.loadPkg(path)
+		if err != nil || p.Types.Scope().Lookup(id) == nil {
+			continue
+		}
+
+		f, it, err := inlineExpr(p, append([]string{id}, elems[len(elems)-n:]...))
+		if err != nil {
+			return Interface{}, true, err
+		}
+		i, err := newInline(p, f, it)
+		return i, true, err
+	}
+	return Interface{}, false, nil
+}
+
+// loadInlineAt locates the inline interface at the position "file:line[:col]",
+// the first one of the line if the column isn't given.
+func (l *Loader) loadInlineAt(pos string) (Interface, error) {
+	m := inlinePosRe.FindStringSubmatch(pos)
+	file := m[1]
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(l.dir(), file)
+	}
+	file, err := filepath.Abs(file)
+	if err != nil {
+		return Interface{}, err
+	}
+	if _, err := os.Stat(file); err != nil {
+		return Interface{}, err
+	}
+	line, _ := strconv.Atoi(m[2])
+	col, _ := strconv.Atoi(m[3])
+
+	pkgs, err := l.loadPkgs([]string{"file=" + file})
+	if err != nil {
+		return Interface{}, err
+	}
+	for _, p := range pkgs {
+		for _, f := range p.Syntax {
+			if p.Fset.Position(f.Pos()).Filename != file {
+				continue
+			}
+			var found *ast.InterfaceType
+			ast.Inspect(f, func(n ast.Node) bool {
+				if it, ok := n.(*ast.InterfaceType); ok && found == nil {
+					if p := p.Fset.Position(it.Pos()); p.Line == line && (col == 0 || p.Column == col) {
+						found = it
+					}
+				}
+				return found == nil
+			})
+			if found == nil {
+				return Interface{}, fmt.Errorf("%s: no inline interface found", pos)
+			}
+			return newInline(p, f, found)
+		}
+	}
+	return Interface{}, fmt.Errorf("%s: file not found in the loaded packages", pos)
+}
+
+// inlineExpr returns the inline interface at the path in p. The first element of the path
+// is a type or a function declared by p, the next ones name fields of structs,
+// methods of the type or of interfaces and parameters or results of functions.
+func inlineExpr(p loadedPackage, path []string) (*ast.File, *ast.InterfaceType, error) {
+	var file *ast.File
+	var expr ast.Expr
+	for _, f := range p.Syntax {
+		for _, decl := range f.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					if spec, ok := spec.(*ast.TypeSpec); ok && spec.Name.Name == path[0] {
+						file, expr = f, spec.Type
+					}
+				}
+			case *ast.FuncDecl:
+				if decl.Recv == nil && decl.Name.Name == path[0] {
+					file, expr = f, decl.Type
+				}
+			}
+		}
+	}
+	if expr == nil {
+		return nil, nil, fmt.Errorf("%s is neither a type nor a function of package %s", path[0], p.PkgPath)
+	}
+
+	for j, name := range path[1:] {
+		var list []*ast.FieldList
+		switch e := unwrapType(expr).(type) {
+		case *ast.StructType:
+			list = []*ast.FieldList{e.Fields}
+		case *ast.InterfaceType:
+			list = []*ast.FieldList{e.Methods}
+		case *ast.FuncType:
+			list = []*ast.FieldList{e.Params, e.Results}
+		}
+		field := fieldByName(list, name)
+		if field == nil && j == 0 {
+			// A parameter of a method of the type.
+			if decl := methodDecl(p, path[0], name); decl != nil {
+				expr = decl.Type
+				continue
+			}
+		}
+		if field == nil {
+			return nil, nil, errorAt(p.Fset.Position(expr.Pos()), "%s has no field, method or parameter %s",
+				strings.Join(path[:j+1], "."), name)
+		}
+		expr = field.Type
+	}
+
+	it, ok := unwrapType(expr).(*ast.InterfaceType)
+	if !ok {
+		return nil, nil, errorAt(p.Fset.Position(expr.Pos()), "%s is not an inline interface", strings.Join(path, "."))
+	}
+	return file, it, nil
+}
+
+// unwrapType returns the element type of pointer, slice, array, map, channel and variadic types.
+func unwrapType(expr ast.Expr) ast.Expr {
+	for {
+		switch e := expr.(type) {
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ArrayType:
+			expr = e.Elt
+		case *ast.Ellipsis:
+			expr = e.Elt
+		case *ast.MapType:
+			expr = e.Value
+		case *ast.ChanType:
+			expr = e.Value
+		default:
+			return expr
+		}
+	}
+}
+
+// fieldByName returns the field of lists named name, nil if there is none.
+func fieldByName(lists []*ast.FieldList, name string) *ast.Field {
+	for _, list := range lists {
+		if list == nil {
+			continue
+		}
+		for _, field := range list.List {
+			for _, n := range field.Names {
+				if n.Name == name {
+					return field
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// methodDecl returns the declaration of the method name of type typ of p, nil if there is none.
+func methodDecl(p loadedPackage, typ string, name string) *ast.FuncDecl {
+	for _, f := range p.Syntax {
+		for _, decl := range f.Decls {
+			if decl, ok := decl.(*ast.FuncDecl); ok && decl.Recv != nil && decl.Name.Name == name && recvName(decl) == typ {
+				return decl
+			}
+		}
+	}
+	return nil
+}
+
+// recvName returns the receiver type name of the method declaration.
+func recvName(decl *ast.FuncDecl) string {
+	expr := decl.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	switch e := expr.(type) {
+	case *ast.IndexExpr:
+		expr = e.X
+	case *ast.IndexListExpr:
+		expr = e.X
+	}
+	if id, ok := expr.(*ast.Ident); ok {
+		return id.Name
+	}
+	return ""
+}
+
+// newInline returns the inline interface it declared in the file f of p.
+// It is named after the declarations enclosing it, e.g. "ServiceStore" for the field store of Service.
+func newInline(p loadedPackage, f *ast.File, it *ast.InterfaceType) (Interface, error) {
+	pos := p.Fset.Position(it.Pos())
+	inline := &inlineIface{pos: it.Pos()}
+
+	var path []string
+	enclosing, _ := astutil.PathEnclosingInterval(f, it.Pos(), it.End())
+	for j := len(enclosing) - 1; j >= 0; j-- {
+		switch n := enclosing[j].(type) {
+		case *ast.TypeSpec:
+			if n.TypeParams != nil {
+				return Interface{}, errorAt(pos, "inline interfaces of generic types can't be mocked")
+			}
+			path = append(path, n.Name.Name)
+		case *ast.FuncDecl:
+			if n.Recv != nil {
+				if _, ok := unwrapType(n.Recv.List[0].Type).(*ast.Ident); !ok {
+					return Interface{}, errorAt(pos, "inline interfaces of methods of generic types can't be mocked")
+				}
+				path = append(path, recvName(n))
+			}
+			if n.Type.TypeParams != nil {
+				return Interface{}, errorAt(pos, "inline interfaces of generic functions can't be mocked")
+			}
+			path = append(path, n.Name.Name)
+		case *ast.Field:
+			if len(n.Names) > 0 {
+				path = append(path, n.Names[0].Name)
+				inline.doc = strings.TrimSpace(n.Doc.Text())
+			}
+		case *ast.ValueSpec:
+			path = append(path, n.Names[0].Name)
+			inline.doc = strings.TrimSpace(n.Doc.Text())
+		}
+	}
+	if len(path) == 0 {
+		path = []string{fmt.Sprintf("Iface%d", pos.Line)}
+	}
+	inline.path = strings.Join(path, ".")
+
+	var name string
+	for _, elem := range path {
+		name += exportName(elem)
+	}
+
+	idecl, ok := p.TypesInfo.TypeOf(it).(*types.Interface)
+	if !ok {
+		return Interface{}, errorAt(pos, "%s is not type-checked", inline.path)
+	}
+	if err := checkInterface(p, inline.path, idecl, pos); err != nil {
+		return Interface{}, err
+	}
+	return Interface{Name: name, pkg: p, typ: idecl, iface: idecl, inline: inline}, nil
+}
@@ -0,0 +1,463 @@
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/imports"
+)
+
+// loadedPackage is a loaded and type-checked package.
+type loadedPackage struct {
+	*packages.Package
+	Dir string
+}
+
+// loadMode is the information loaded for the packages of interfaces.
+const loadMode = packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+	packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps
+
+// dir returns the directory to resolve interfaces and patterns in.
+func (l *Loader) dir() string {
+	if l.Dir != "" {
+		return l.Dir
+	}
+	wd, _ := os.Getwd()
+	return wd
+}
+
+// config returns the config loading packages with the build options of l.
+func (l *Loader) config() *packages.Config {
+	cfg := &packages.Config{Mode: loadMode, Dir: l.dir(), Tests: l.Tests}
+	if l.Tags != "" {
+		cfg.BuildFlags = []string{"-tags=" + l.Tags}
+	}
+	if l.GOOS != "" || l.GOARCH != "" {
+		cfg.Env = os.Environ()
+		if l.GOOS != "" {
+			cfg.Env = append(cfg.Env, "GOOS="+l.GOOS)
+		}
+		if l.GOARCH != "" {
+			cfg.Env = append(cfg.Env, "GOARCH="+l.GOARCH)
+		}
+	}
+	return cfg
+}
+
+// findInterface returns the import path and identifier of an interface.
+// For example, given "http.ResponseWriter", findInterface returns
+// "net/http", "ResponseWriter".
+// If a fully qualified interface is given, such as "net/http.ResponseWriter",
+// it simply parses the input.
+func (l *Loader) findInterface(iface string) (path string, id string, err error) {
+	if len(strings.Fields(iface)) != 1 {
+		return "", "", fmt.Errorf("couldn't parse interface: %s", iface)
+	}
+
+	srcDir := l.dir()
+	srcPath := filepath.Join(srcDir, "__go_impl__.go")
+
+	if slash := strings.LastIndex(iface, "/"); slash > -1 {
+		// package path provided
+		dot := strings.LastIndex(iface, ".")
+		// make sure iface does not end with "/" (e.g. reject net/http/)
+		if slash+1 == len(iface) {
+			return "", "", fmt.Errorf("interface name cannot end with a '/' character: %s", iface)
+		}
+		// make sure iface does not end with "." (e.g. reject net/http.)
+		if dot+1 == len(iface) {
+			return "", "", fmt.Errorf("interface name cannot end with a '.' character: %s", iface)
+		}
+		// make sure iface has a "." after "/" (e.g. reject net/http/httputil).
+		// The last path element may contain dots too (e.g. gopkg.in/yaml.v3.Marshaler)
+		if strings.Count(iface[slash:], ".") == 0 {
+			return "", "", fmt.Errorf("invalid interface name: %s", iface)
+		}
+		l.logf("resolved %s to type %s of package %s", iface, iface[dot+1:], iface[:dot])
+		return iface[:dot], iface[dot+1:], nil
+	}
+
+	src := []byte("package hack\n" + "var i " + iface)
+	// If we couldn't determine the import path, goimports will
+	// auto fix the import path.
+	imp, err := imports.Process(srcPath, src, nil)
+	if err != nil {
+		return "", "", fmt.Errorf("couldn't parse interface: %s", iface)
+	}
+
+	// imp should now contain an appropriate import.
+	// Parse out the import and the identifier.
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, srcPath, imp, 0)
+	if err != nil {
+		return "", "", fmt.Errorf("couldn't parse interface %s: %v", iface, err)
+	}
+	if len(f.Imports) == 0 {
+		return "", "", fmt.Errorf("unrecognized interface: %s", iface)
+	}
+	raw := f.Imports[0].Path.Value   // "io"
+	path, err = strconv.Unquote(raw) // io
+	if err != nil {
+		return "", "", fmt.Errorf("unrecognized interface %s: invalid import path %s", iface, raw)
+	}
+	decl := f.Decls[len(f.Decls)-1].(*ast.GenDecl) // var i io.Reader
+	spec := decl.Specs[0].(*ast.ValueSpec)         // i io.Reader
+	sel, ok := spec.Type.(*ast.SelectorExpr)       // io.Reader
+	if !ok {
+		return "", "", fmt.Errorf("unrecognized interface %s: want <package>.<interface>", iface)
+	}
+	id = sel.Sel.Name // Reader
+	l.logf("resolved %s to type %s of package %s found by goimports in %s", iface, id, path, srcDir)
+	return path, id, nil
+}
+
+// testVariants returns the packages of pkgs with the most files per import path
+// in the order of pkgs. If tests are included, a package is loaded along with
+// its variant compiled with _test.go files, which is preferred.
+// Test binaries are skipped.
+func testVariants(pkgs []*packages.Package) []*packages.Package {
+	var res []*packages.Package
+	idx := map[string]int{}
+	for _, pkg := range pkgs {
+		if strings.HasSuffix(pkg.ID, ".test") {
+			continue
+		}
+		i, ok := idx[pkg.PkgPath]
+		if !ok {
+			idx[pkg.PkgPath] = len(res)
+			res = append(res, pkg)
+			continue
+		}
+		if len(pkg.Syntax) > len(res[i].Syntax) {
+			res[i] = pkg
+		}
+	}
+	return res
+}
+
+// loadPkg loads the package with the import path.
+func (l *Loader) loadPkg(path string) (loadedPackage, error) {
+	if p, ok := l.pkgs[path]; ok {
+		return p, nil
+	}
+
+	// An external test package is loaded along with the package it tests.
+	pattern := path
+	if l.Tests {
+		pattern = strings.TrimSuffix(path, "_test")
+	}
+	pkgs, err := packages.Load(l.config(), pattern)
+	if err != nil {
+		return loadedPackage{}, fmt.Errorf("couldn't find package %s: %v", path, err)
+	}
+
+	var found []*packages.Package
+	for _, pkg := range testVariants(pkgs) {
+		if pkg.PkgPath == path || len(pkgs) == 1 {
+			found = append(found, pkg)
+		}
+	}
+	if len(found) != 1 {
+		return loadedPackage{}, fmt.Errorf("couldn't find package %s: %d packages found", path, len(found))
+	}
+
+	pkg := found[0]
+	l.logPkg(pkg)
+	if ds := l.pkgErrors(pkg); len(ds) > 0 {
+		return loadedPackage{}, ds
+	}
+	if len(pkg.Syntax) == 0 {
+		return loadedPackage{}, fmt.Errorf("couldn't find package %s: no Go files", path)
+	}
+
+	return l.cache(pkg), nil
+}
+
+// cache caches the loaded package pkg.
+func (l *Loader) cache(pkg *packages.Package) loadedPackage {
+	if l.pkgs == nil {
+		l.pkgs = map[string]loadedPackage{}
+	}
+	p := loadedPackage{Package: pkg, Dir: filepath.Dir(pkg.GoFiles[0])}
+	l.pkgs[pkg.PkgPath] = p
+	return p
+}
+
+// loadPkgs loads the packages matching patterns.
+func (l *Loader) loadPkgs(patterns []string) ([]loadedPackage, error) {
+	pkgs, err := packages.Load(l.config(), patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []loadedPackage
+	var errs Diagnostics
+	for _, pkg := range testVariants(pkgs) {
+		l.logPkg(pkg)
+		if ds := l.pkgErrors(pkg); len(ds) > 0 {
+			errs = append(errs, ds...)
+			continue
+		}
+		if len(pkg.Syntax) == 0 {
+			continue
+		}
+		res = append(res, l.cache(pkg))
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return res, nil
+}
+
+// typeSpec locates the *ast.TypeSpec for type id in the import path.
+func (l *Loader) typeSpec(path string, id string) (loadedPackage, *ast.TypeSpec, error) {
+	pkg, err := l.loadPkg(path)
+	if err != nil {
+		return loadedPackage{}, nil, err
+	}
+
+	var found *ast.TypeSpec
+	typeSpecs(pkg, func(spec *ast.TypeSpec, doc *ast.CommentGroup) bool {
+		if spec.Name.Name == id {
+			found = spec
+		}
+		return found == nil
+	})
+	if found == nil {
+		return loadedPackage{}, nil, fmt.Errorf("type %s not found in package %s (%s)", id, path, pkg.Dir)
+	}
+	l.logf("found type %s at %s", id, pkg.Fset.Position(found.Pos()))
+	return pkg, found, nil
+}
+
+// typeSpecs calls fn for every type spec of pkg until fn returns false.
+// doc is the doc comment of the spec or, for a spec declared alone, of its declaration.
+func typeSpecs(pkg loadedPackage, fn func(spec *ast.TypeSpec, doc *ast.CommentGroup) bool) {
+	for _, f := range pkg.Syntax {
+		for _, decl := range f.Decls {
+			decl, ok := decl.(*ast.GenDecl)
+			if !ok || decl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				spec := spec.(*ast.TypeSpec)
+				doc := spec.Doc
+				if doc == nil && !decl.Lparen.IsValid() {
+					doc = decl.Doc
+				}
+				if !fn(spec, doc) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// loadInterface locates iface and type-checks its package.
+func (l *Loader) loadInterface(iface string) (Interface, error) {
+	// Special case for the built-in error interface.
+	if iface == "error" {
+		typ := types.Universe.Lookup("error").Type()
+		return Interface{Name: iface, typ: typ, iface: typ.Underlying().(*types.Interface)}, nil
+	}
+
+	if inlinePosRe.MatchString(iface) {
+		return l.loadInlineAt(iface)
+	}
+
+	p, id, typ, err := l.loadType(iface)
+	if err != nil {
+		// The interface may be declared inline, e.g. pkg.Service.store.
+		if i, found, inlineErr := l.loadInlinePath(iface); found {
+			return i, inlineErr
+		}
+		return Interface{}, err
+	}
+	return newInterface(p, id, typ, true)
+}
+
+// loadType locates the named type typ, e.g. "io.Reader" or "pkg.Repo[pkg.User]",
+// type-checks its package and instantiates it if typ has type arguments.
+// It returns the package and the identifier the type is declared with.
+func (l *Loader) loadType(typ string) (loadedPackage, string, types.Type, error) {
+	// Split type arguments of an instantiated generic type.
+	name, args, err := splitTypeArgs(typ)
+	if err != nil {
+		return loadedPackage{}, "", nil, err
+	}
+
+	// Locate the type.
+	path, id, err := l.findInterface(name)
+	if err != nil {
+		return loadedPackage{}, "", nil, err
+	}
+
+	// Parse the package and find the type declaration.
+	p, spec, err := l.typeSpec(path, id)
+	if err != nil {
+		return loadedPackage{}, "", nil, err
+	}
+
+	obj := p.TypesInfo.Defs[spec.Name]
+	if obj == nil {
+		return loadedPackage{}, "", nil, errorAt(p.Fset.Position(spec.Pos()), "%s is not type-checked", id)
+	}
+
+	t := types.Unalias(obj.Type())
+	if args != nil {
+		if t, err = l.instantiate(t, args, p.Types.Scope()); err != nil {
+			return loadedPackage{}, "", nil, errorAt(p.Fset.Position(spec.Pos()), "couldn't instantiate %s: %v", typ, err)
+		}
+	}
+	return p, id, t, nil
+}
+
+// newInterface checks that typ declared as id in p is an interface or,
+// if funcs is set, a function type which can be mocked.
+func newInterface(p loadedPackage, id string, typ types.Type, funcs bool) (Interface, error) {
+	var pos token.Position
+	if obj := p.Types.Scope().Lookup(id); obj != nil {
+		pos = p.Fset.Position(obj.Pos())
+	}
+
+	if sig, ok := typ.Underlying().(*types.Signature); ok && funcs {
+		if _, ok := typ.(*types.Named); !ok {
+			return Interface{}, errorAt(pos, "%s is an alias of an unnamed function type", id)
+		}
+		if hasInvalid(sig) {
+			return Interface{}, errorAt(pos, "%s has invalid types; -v shows the type errors", id)
+		}
+		return Interface{Name: id, pkg: p, typ: typ, sig: sig}, nil
+	}
+
+	idecl, ok := typ.Underlying().(*types.Interface)
+	if !ok {
+		if funcs {
+			return Interface{}, errorAt(pos, "%s is neither an interface nor a function type", id)
+		}
+		return Interface{}, errorAt(pos, "%s is not an interface", id)
+	}
+
+	if err := checkInterface(p, id, idecl, pos); err != nil {
+		return Interface{}, err
+	}
+	return Interface{Name: id, pkg: p, typ: typ, iface: idecl}, nil
+}
+
+// checkInterface checks that the interface idecl of p, declared as id at pos, can be mocked.
+func checkInterface(p loadedPackage, id string, idecl *types.Interface, pos token.Position) error {
+	if !idecl.IsMethodSet() {
+		return errorAt(pos, "%s is a constraint interface, it can't be mocked", id)
+	}
+
+	if idecl.NumMethods() == 0 {
+		return errorAt(pos, "%s is an empty interface", id)
+	}
+
+	for i := 0; i < idecl.NumMethods(); i++ {
+		if m := idecl.Method(i); hasInvalid(m.Type()) {
+			errs := typeErrorsIn(p, m.Pos())
+			if len(errs) == 0 {
+				return errorAt(p.Fset.Position(m.Pos()), "method %s of %s has invalid types; -v shows the type errors", m.Name(), id)
+			}
+			return errorAt(p.Fset.Position(m.Pos()), "method %s of %s has invalid types: %s", m.Name(), id, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+// pkgInterfaces returns the exported interfaces of p which can be mocked, ordered by name.
+func (l *Loader) pkgInterfaces(p loadedPackage) []Interface {
+	var ifaces []Interface
+	scope := p.Types.Scope()
+	for _, name := range scope.Names() {
+		tn, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || !tn.Exported() {
+			continue
+		}
+
+		i, err := newInterface(p, name, types.Unalias(tn.Type()), false)
+		if err != nil {
+			l.logf("skipped %v", err)
+			continue
+		}
+		ifaces = append(ifaces, i)
+	}
+	return ifaces
+}
+
+// doc returns the doc comment of the interface or, for an inline interface,
+// of the field declaring it, empty if its source isn't loaded.
+func (i Interface) doc() string {
+	if i.pkg.Package == nil {
+		return ""
+	}
+	if i.inline != nil {
+		return i.inline.doc
+	}
+	var doc string
+	typeSpecs(i.pkg, func(spec *ast.TypeSpec, cg *ast.CommentGroup) bool {
+		if spec.Name.Name != i.Name {
+			return true
+		}
+		doc = strings.TrimSpace(cg.Text())
+		return false
+	})
+	return doc
+}
+
+// fieldDoc returns the doc comment text of the interface method, struct field
+// or method declaration at pos in pkg or its dependencies, empty if its source isn't found.
+// Dependencies are loaded without syntax, so their files are parsed.
+func fieldDoc(pkg *packages.Package, pos token.Pos) string {
+	p := pkg.Fset.Position(pos)
+	if !p.IsValid() {
+		return ""
+	}
+
+	var f *ast.File
+	fset := pkg.Fset
+	for _, syntax := range pkg.Syntax {
+		if syntax.FileStart <= pos && pos < syntax.FileEnd {
+			f = syntax
+		}
+	}
+	if f == nil {
+		fset = token.NewFileSet()
+		var err error
+		if f, err = parser.ParseFile(fset, p.Filename, nil, parser.ParseComments); err != nil {
+			return ""
+		}
+	}
+
+	var doc string
+	found := false
+	at := func(name *ast.Ident) bool {
+		np := fset.Position(name.Pos())
+		return np.Line == p.Line && np.Column == p.Column
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Field:
+			for _, name := range n.Names {
+				if at(name) {
+					doc, found = strings.TrimSpace(n.Doc.Text()), true
+				}
+			}
+		case *ast.FuncDecl:
+			if at(n.Name) {
+				doc, found = strings.TrimSpace(n.Doc.Text()), true
+			}
+		}
+		return !found
+	})
+	return doc
+}
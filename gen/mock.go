@@ -0,0 +1,640 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/imports"
+)
+
+// mockPkgPath is the import path of the mocking engine used by generated mocks.
+const mockPkgPath = "github.com/unkeep/gomock/mock"
+
+// funcsig returns the signature sig of a function or method named name.
+// Types are printed using q for package qualification.
+func funcsig(name string, sig *types.Signature, q types.Qualifier) Func {
+	return Func{
+		Name:     name,
+		Params:   params(sig.Params(), sig.Variadic(), q),
+		Res:      params(sig.Results(), false, q),
+		Variadic: sig.Variadic(),
+	}
+}
+
+func params(tuple *types.Tuple, variadic bool, q types.Qualifier) []Param {
+	var params []Param
+	for i := 0; i < tuple.Len(); i++ {
+		v := tuple.At(i)
+		typ := types.TypeString(v.Type(), q)
+		if variadic && i == tuple.Len()-1 {
+			typ = "..." + types.TypeString(v.Type().(*types.Slice).Elem(), q)
+		}
+		params = append(params, Param{Name: v.Name(), Type: typ, Variadic: variadic && i == tuple.Len()-1})
+	}
+	return params
+}
+
+// typeParams returns the type parameters of a generic interface.
+// It returns nil for non-generic and instantiated interfaces.
+func (i Interface) typeParams() *types.TypeParamList {
+	if named, ok := i.typ.(*types.Named); ok && named.TypeArgs().Len() == 0 {
+		return named.TypeParams()
+	}
+	return nil
+}
+
+// typeParamsDecl returns the type parameters declaration of a generic interface,
+// e.g. "[K comparable, V any]", and their usage, e.g. "[K, V]".
+func (i Interface) typeParamsDecl(q types.Qualifier) (decl string, use string) {
+	tparams := i.typeParams()
+	if tparams.Len() == 0 {
+		return "", ""
+	}
+
+	var decls, uses []string
+	for j := 0; j < tparams.Len(); j++ {
+		tp := tparams.At(j)
+		decls = append(decls, tp.Obj().Name()+" "+types.TypeString(tp.Constraint(), q))
+		uses = append(uses, tp.Obj().Name())
+	}
+	return "[" + strings.Join(decls, ", ") + "]", "[" + strings.Join(uses, ", ") + "]"
+}
+
+// expr returns the interface type expression used in method expressions,
+// e.g. "pkg.Storage", "pkg.Repo[T]", "pkg.Repo[pkg.User]" or "(interface{Get(string) []byte})".
+func (i Interface) expr(q types.Qualifier) string {
+	if i.inline != nil {
+		// An interface literal is parenthesized to be used in method expressions.
+		return "(" + types.TypeString(unnamedParams(i.iface), q) + ")"
+	}
+	if named, ok := i.typ.(*types.Named); ok && named.TypeArgs().Len() > 0 {
+		return types.TypeString(i.typ, q)
+	}
+
+	var pkg *types.Package
+	if i.pkg.Package != nil {
+		pkg = i.pkg.Types
+	}
+	expr := qualified(pkg, i.Name, q)
+	_, use := i.typeParamsDecl(q)
+	return expr + use
+}
+
+// mockedName returns the interface name the mock is named after: the name suffixed
+// by the type arguments of an instantiation, e.g. "RepoUser" for "Repo[pkg.User]",
+// so the mocks of a generic interface and of its instantiations don't collide.
+func (i Interface) mockedName() string {
+	named, ok := i.typ.(*types.Named)
+	if !ok || i.inline != nil || named.TypeArgs().Len() == 0 {
+		return i.Name
+	}
+
+	name := i.Name
+	for j := 0; j < named.TypeArgs().Len(); j++ {
+		arg := types.TypeString(named.TypeArgs().At(j), func(*types.Package) string { return "" })
+		words := strings.FieldsFunc(arg, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+		})
+		for _, word := range words {
+			name += exportName(word)
+		}
+	}
+	return name
+}
+
+// unnamedParams returns the interface iface without names of the method parameters and results,
+// so the names don't collide with the ones of the mock methods.
+func unnamedParams(iface *types.Interface) *types.Interface {
+	unnamed := func(tuple *types.Tuple) *types.Tuple {
+		vars := make([]*types.Var, tuple.Len())
+		for j := range vars {
+			vars[j] = types.NewParam(token.NoPos, nil, "", tuple.At(j).Type())
+		}
+		return types.NewTuple(vars...)
+	}
+
+	methods := make([]*types.Func, iface.NumMethods())
+	for j := range methods {
+		m := iface.Method(j)
+		sig := m.Type().(*types.Signature)
+		sig = types.NewSignatureType(nil, nil, nil, unnamed(sig.Params()), unnamed(sig.Results()), sig.Variadic())
+		methods[j] = types.NewFunc(m.Pos(), m.Pkg(), m.Name(), sig)
+	}
+	return types.NewInterfaceType(methods, nil).Complete()
+}
+
+// sampleTypeArgs returns type arguments satisfying the constraints of a generic interface,
+// e.g. [int, any] for Repo[K comparable, V any]. They are picked from the types of
+// the constraint terms, int, string, any and the constraint itself. It returns nil if none are found.
+func (i Interface) sampleTypeArgs() []types.Type {
+	tparams := i.typeParams()
+	candidates := make([][]types.Type, tparams.Len())
+	for j := range candidates {
+		constraint := tparams.At(j).Constraint()
+		candidates[j] = append(termTypes(constraint),
+			types.Typ[types.Int], types.Typ[types.String], types.Universe.Lookup("any").Type())
+		if iface, ok := constraint.Underlying().(*types.Interface); ok && iface.IsMethodSet() && iface.NumMethods() > 0 {
+			// An interface without type terms implements itself.
+			candidates[j] = append(candidates[j], constraint)
+		}
+	}
+
+	// The search is limited as every attempt instantiates the interface.
+	const maxAttempts = 1000
+	attempts := 0
+	args := make([]types.Type, tparams.Len())
+	var search func(j int) bool
+	search = func(j int) bool {
+		if j == len(args) {
+			attempts++
+			_, err := types.Instantiate(nil, i.typ, args, true)
+			return err == nil
+		}
+		for _, c := range candidates[j] {
+			if attempts == maxAttempts {
+				return false
+			}
+			args[j] = c
+			if search(j + 1) {
+				return true
+			}
+		}
+		return false
+	}
+	if !search(0) {
+		return nil
+	}
+	return args
+}
+
+// termTypes returns the types of the type set terms of a constraint, e.g. int and string for ~int | string.
+func termTypes(constraint types.Type) []types.Type {
+	iface, ok := constraint.Underlying().(*types.Interface)
+	if !ok {
+		return nil
+	}
+
+	var res []types.Type
+	for j := 0; j < iface.NumEmbeddeds(); j++ {
+		switch t := iface.EmbeddedType(j).(type) {
+		case *types.Union:
+			for k := 0; k < t.Len(); k++ {
+				res = append(res, t.Term(k).Type())
+			}
+		default:
+			if _, ok := t.Underlying().(*types.Interface); ok {
+				res = append(res, termTypes(t)...)
+			} else {
+				res = append(res, t)
+			}
+		}
+	}
+	return res
+}
+
+// funcs returns the set of methods required to implement the interface.
+// It is called funcs rather than methods because the
+// function descriptions are functions; there is no receiver.
+// The method set includes methods of embedded interfaces, each method
+// is listed once even if it is declared by several embedded interfaces.
+// The mock of a function type has the only method Fn returning the function,
+// its description is the function signature.
+func (i Interface) funcs(q types.Qualifier) []Func {
+	if i.sig != nil {
+		return []Func{funcsig("Fn", i.sig, q)}
+	}
+	var fns []Func
+	for j := 0; j < i.iface.NumMethods(); j++ {
+		m := i.iface.Method(j)
+		fn := funcsig(m.Name(), m.Type().(*types.Signature), q)
+		if i.pkg.Package != nil {
+			fn.Doc = fieldDoc(i.pkg.Package, m.Pos())
+		}
+		fns = append(fns, fn)
+	}
+	return fns
+}
+
+// importSet assigns unique names to the packages referenced by the generated file.
+type importSet struct {
+	self  string            // import path of the generated file package
+	names map[string]string // import path => name
+	used  identSet          // names of imports and identifiers they must not shadow
+	list  []Import
+}
+
+func newImportSet(self string) *importSet {
+	return &importSet{
+		self:  self,
+		names: map[string]string{},
+		used:  identSet{},
+	}
+}
+
+// reserve prevents packages from being imported with the given names.
+func (s *importSet) reserve(names ...string) {
+	for _, name := range names {
+		s.used[name] = true
+	}
+}
+
+// add imports pkg and returns the name to qualify its identifiers with.
+func (s *importSet) add(path string, name string) string {
+	if path == s.self {
+		return ""
+	}
+	if n, ok := s.names[path]; ok {
+		return n
+	}
+
+	unique := s.used.unique(name)
+	s.names[path] = unique
+
+	imp := Import{Path: path}
+	if unique != name || name != filepath.Base(path) {
+		imp.Name = unique
+	}
+	s.list = append(s.list, imp)
+	return unique
+}
+
+// identSet is a set of identifiers declared in a scope.
+type identSet map[string]bool
+
+// unique adds name to the set and returns it. If name is already in the set,
+// the name is suffixed by the smallest number making it unique, e.g. "m1".
+func (s identSet) unique(name string) string {
+	unique := name
+	for i := 1; s[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	s[unique] = true
+	return unique
+}
+
+// numbered adds the name prefix<N> with the smallest N >= n which isn't in the set
+// to the set and returns it.
+func (s identSet) numbered(prefix string, n int) string {
+	name := fmt.Sprintf("%s%d", prefix, n)
+	for ; s[name]; n++ {
+		name = fmt.Sprintf("%s%d", prefix, n+1)
+	}
+	s[name] = true
+	return name
+}
+
+func (s identSet) copy() identSet {
+	c := make(identSet, len(s))
+	for name := range s {
+		c[name] = true
+	}
+	return c
+}
+
+// qualifier is a types.Qualifier which imports the packages of printed types.
+func (s *importSet) qualifier(pkg *types.Package) string {
+	return s.add(pkg.Path(), pkg.Name())
+}
+
+// qualified returns id qualified by q.
+func qualified(pkg *types.Package, id string, q types.Qualifier) string {
+	if pkg == nil {
+		return id
+	}
+	if name := q(pkg); name != "" {
+		return name + "." + id
+	}
+	return id
+}
+
+// sorted returns the imports ordered by path.
+func (s *importSet) sorted() []Import {
+	list := append([]Import(nil), s.list...)
+	sort.Slice(list, func(i, j int) bool { return list[i].Path < list[j].Path })
+	return list
+}
+
+// constructorName returns the name of the constructor of the mock type,
+// exported if the mock type is exported.
+func constructorName(mock string) string {
+	prefix := "new"
+	if token.IsExported(mock) {
+		prefix = "New"
+	}
+	return prefix + exportName(mock)
+}
+
+// exportName returns name with the first letter in upper case.
+func exportName(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(r)) + name[size:]
+}
+
+// mockNamer names mocks by a pattern like "Fake{{.Iface}}".
+type mockNamer struct {
+	pattern  *template.Template
+	exported bool // export names, e.g. "mockReader" becomes "MockReader"
+}
+
+func newMockNamer(pattern string, exported bool) (mockNamer, error) {
+	if pattern == "" {
+		pattern = "mock{{.Iface}}"
+	}
+	t, err := template.New("name").Option("missingkey=error").Parse(pattern)
+	if err != nil {
+		return mockNamer{}, fmt.Errorf("invalid mock name pattern: %v", err)
+	}
+	return mockNamer{pattern: t, exported: exported}, nil
+}
+
+// name returns the mock type name of the iface interface.
+func (n mockNamer) name(iface string) (string, error) {
+	var buf bytes.Buffer
+	if err := n.pattern.Execute(&buf, map[string]string{"Iface": iface}); err != nil {
+		return "", fmt.Errorf("invalid mock name pattern: %v", err)
+	}
+
+	name := buf.String()
+	if n.exported {
+		name = exportName(name)
+	}
+	if !token.IsIdentifier(name) {
+		return "", fmt.Errorf("invalid mock name %q of %s", name, iface)
+	}
+	return name, nil
+}
+
+// genFile prints a nicely formatted Go file with the mocks of ifaces rendered by t.
+// pkgName and pkgPath are the name and the import path of the generated file package.
+// pkgPath may be empty if the package is not importable.
+func genFile(path string, ifaces []Interface, pkgName string, pkgPath string, t *Template) ([]byte, error) {
+	declared, err := mockNames(path, ifaces, pkgName, pkgPath)
+	if err != nil {
+		return nil, err
+	}
+
+	imps := newImportSet(pkgPath)
+	for _, i := range ifaces {
+		// Imports must not collide with the declarations of the package
+		// and must not be shadowed by the mock type parameters.
+		if i.pkg.Package != nil && i.pkg.PkgPath == pkgPath {
+			imps.reserve(i.pkg.Types.Scope().Names()...)
+		}
+		tparams := i.typeParams()
+		for j := 0; j < tparams.Len(); j++ {
+			imps.reserve(tparams.At(j).Obj().Name())
+		}
+	}
+	imps.add(mockPkgPath, "mock")
+
+	fileData := FileData{Version: Version, Package: pkgName}
+	for _, i := range ifaces {
+		data, err := mockData(i, imps, declared)
+		if err != nil {
+			return nil, err
+		}
+		fileData.Mocks = append(fileData.Mocks, data)
+	}
+
+	// Packages imported by templates must not be shadowed by the mock parameters.
+	for _, m := range fileData.Mocks {
+		imps.reserve(m.Recv, m.RecorderRecv, m.CallRecv, m.CtorT, m.CtorOpts)
+		for _, fn := range m.Methods {
+			for _, p := range append(fn.Params, fn.Res...) {
+				imps.reserve(p.Name)
+			}
+		}
+	}
+	fileData.Imports = imps.sorted()
+
+	if t == nil {
+		t = defaultTemplate
+	}
+	src, err := t.execute(fileData, imps)
+	if err != nil {
+		return nil, err
+	}
+
+	// Imports are known, so they are only grouped and sorted.
+	return imports.Process("", src, &imports.Options{Comments: true, FormatOnly: true})
+}
+
+// mockNames returns the package-level names declared in the package of the file path
+// by the other files and by the mocks and constructors of ifaces. The names of the mocks
+// and constructors must be unique.
+func mockNames(path string, ifaces []Interface, pkgName string, pkgPath string) (identSet, error) {
+	declared := identSet{}
+	for _, i := range ifaces {
+		if i.pkg.Package == nil || i.pkg.PkgPath != pkgPath {
+			continue
+		}
+		// The declarations of the file are replaced by the generated ones.
+		scope := i.pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			if i.pkg.Fset.Position(scope.Lookup(name).Pos()).Filename != path {
+				declared[name] = true
+			}
+		}
+		break
+	}
+
+	mocks := map[string]Interface{} // by mock and constructor names
+	for _, i := range ifaces {
+		for _, name := range []string{i.Mock, constructorName(i.Mock)} {
+			if declared[name] {
+				return nil, fmt.Errorf("%s: %s collides with a declaration of package %s, rename the mock", i.Name, name, pkgName)
+			}
+			if other, ok := mocks[name]; ok {
+				return nil, fmt.Errorf("%s: %s collides with the mock of %s, rename the mock", i, name, other)
+			}
+			mocks[name] = i
+		}
+	}
+	for name := range mocks {
+		declared[name] = true
+	}
+	return declared, nil
+}
+
+// Mockable returns why the mock of i placed by Place can't be generated, nil if it can.
+func (i Interface) Mockable() error {
+	return i.mockableIn(i.outPath)
+}
+
+// mockableIn returns why the mock of i can't be generated into the package with the import path self.
+func (i Interface) mockableIn(self string) error {
+	var ifacePkg *types.Package
+	if i.pkg.Package != nil {
+		ifacePkg = i.pkg.Types
+	}
+
+	if i.xtest() && ifacePkg.Path() != self {
+		return fmt.Errorf("%s.%s: external test package %s can't be imported, the mock must be generated into a _test.go file of %s",
+			ifacePkg.Name(), i.Name, ifacePkg.Path(), i.pkg.Dir)
+	}
+
+	if i.iface != nil && ifacePkg != nil && ifacePkg.Path() != self {
+		for j := 0; j < i.iface.NumMethods(); j++ {
+			if m := i.iface.Method(j); !m.Exported() {
+				return fmt.Errorf("%s.%s: unexported method %s can't be implemented outside of %s",
+					ifacePkg.Name(), i.Name, m.Name(), ifacePkg.Path())
+			}
+		}
+	}
+
+	for j := 0; i.iface != nil && j < i.iface.NumMethods(); j++ {
+		switch name := i.iface.Method(j).Name(); name {
+		case "M", "EXPECT", "ON":
+			return fmt.Errorf("%s: method %s collides with the mock.M field or EXPECT/ON recorders of the generated mock",
+				i.Name, name)
+		}
+	}
+
+	return nil
+}
+
+// mockData returns the template data of the mock of iface.
+// Packages referenced by the mock are added to imps, the recorder and call types
+// are named apart from the declared package-level names and added to them.
+func mockData(iface Interface, imps *importSet, declared identSet) (MockData, error) {
+	var ifacePkg *types.Package
+	if iface.pkg.Package != nil {
+		ifacePkg = iface.pkg.Types
+	}
+
+	if err := iface.mockableIn(imps.self); err != nil {
+		return MockData{}, err
+	}
+
+	data := MockData{
+		Mock:      iface.Mock,
+		New:       constructorName(iface.Mock),
+		MockPkg:   imps.add(mockPkgPath, "mock"),
+		Iface:     iface.Name,
+		IfaceFull: iface.expr(imps.qualifier),
+		Source:    iface.String(),
+		Doc:       iface.doc(),
+		Methods:   iface.funcs(imps.qualifier),
+		FuncType:  iface.sig != nil,
+	}
+	if ifacePkg != nil {
+		data.IfacePath = ifacePkg.Path()
+	}
+	if ifacePkg != nil && iface.inline == nil {
+		// An inline interface is referred to by its literal.
+		data.IfacePkg = imps.add(ifacePkg.Path(), ifacePkg.Name())
+	}
+	data.TypeParams, data.TypeArgs = iface.typeParamsDecl(imps.qualifier)
+
+	data.Recorder = declared.unique(data.Mock + "Recorder")
+	data.Calls = map[string]string{}
+	for _, fn := range data.Methods {
+		data.Calls[fn.Name] = declared.unique(data.Mock + fn.Name + "Call")
+	}
+
+	// Names of parameters, results and receivers must not shadow
+	// the identifiers the mock methods refer to.
+	reserved := identSet{"append": true, data.MockPkg: true, data.Mock: true, data.Recorder: true}
+	for _, call := range data.Calls {
+		reserved[call] = true
+	}
+	expr, err := parser.ParseExpr(data.IfaceFull)
+	if err != nil {
+		return MockData{}, fmt.Errorf("%s: %v", data.IfaceFull, err)
+	}
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok {
+			reserved[id.Name] = true
+		}
+		return true
+	})
+
+	used := reserved.copy()
+	for j := range data.Methods {
+		addParamNames(&data.Methods[j], reserved)
+		for _, p := range append(data.Methods[j].Params, data.Methods[j].Res...) {
+			used[p.Name] = true
+		}
+	}
+	data.Recv, data.RecorderRecv, data.CallRecv = used.unique("m"), used.unique("r"), used.unique("c")
+
+	fields := identSet{}
+	for _, fn := range data.Methods {
+		fields[fn.Name] = true
+	}
+	data.RecorderMock, data.RecorderDeclare = fields.unique("m"), fields.unique("declare")
+
+	ctorScope := reserved.copy()
+	data.CtorT, data.CtorOpts = ctorScope.unique("t"), ctorScope.unique("opts")
+
+	if data.FuncType {
+		// The mock of a function type doesn't implement it, there is nothing to assert.
+		return data, nil
+	}
+	if data.TypeParams == "" {
+		data.AssertIface = data.IfaceFull
+	} else if args := iface.sampleTypeArgs(); args != nil {
+		inst, _ := types.Instantiate(nil, iface.typ, args, false)
+		data.AssertIface = types.TypeString(inst, imps.qualifier)
+		var strs []string
+		for _, arg := range args {
+			strs = append(strs, types.TypeString(arg, imps.qualifier))
+		}
+		data.AssertArgs = "[" + strings.Join(strs, ", ") + "]"
+	}
+	return data, nil
+}
+
+// outputPkg returns the name and the import path of the package located in dir.
+// It returns empty ones if there is no package in dir.
+func outputPkg(dir string) (name string, path string) {
+	cfg := &packages.Config{Mode: packages.NeedName, Dir: dir}
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil || len(pkgs) != 1 || pkgs[0].Name == "" {
+		return "", ""
+	}
+	return pkgs[0].Name, pkgs[0].PkgPath
+}
+
+// addParamNames names unnamed parameters and results of f "in<N>" and "out<N>" by their positions
+// and renames the ones colliding with each other or with the reserved identifiers.
+func addParamNames(f *Func, reserved identSet) {
+	used := reserved.copy()
+	all := append(append([]*Param{}, paramPtrs(f.Params)...), paramPtrs(f.Res)...)
+
+	// Named ones are kept unless they collide, unnamed ones get the remaining names.
+	for _, p := range all {
+		if p.Name != "" && p.Name != "_" {
+			p.Name = used.unique(p.Name)
+		}
+	}
+	for i := range f.Params {
+		if p := &f.Params[i]; p.Name == "" || p.Name == "_" {
+			p.Name = used.numbered("in", i+1)
+		}
+	}
+	for i := range f.Res {
+		if p := &f.Res[i]; p.Name == "" || p.Name == "_" {
+			p.Name = used.numbered("out", i+1)
+		}
+	}
+}
+
+func paramPtrs(params []Param) []*Param {
+	ptrs := make([]*Param, len(params))
+	for i := range params {
+		ptrs[i] = &params[i]
+	}
+	return ptrs
+}
@@ -0,0 +1,306 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+)
+
+// The data model of mock templates.
+//
+// A generated file is rendered by the "file" template with FileData.
+// It renders every mock by the "mock" template with MockData, the mocks
+// of function types by the "func" one. Both render the typed recorders
+// by the "recorder" template.
+// A custom template parsed by ParseTemplate replaces the "mock" template,
+// which then renders the mocks of function types too (see MockData.FuncType);
+// it may also redefine the other ones by {{define "func"}}...{{end}}.
+//
+// Besides the standard functions, templates can call import, which imports
+// a package into the generated file and returns the name to qualify its
+// identifiers with, empty for the package of the file itself:
+//
+//	{{import "log"}}.Printf("%s called", "{{.Name}}")
+//	{{import "gopkg.in/yaml.v3" "yaml"}}.Marshal(v)
+//
+// The name defaults to the last element of the path without a major version suffix.
+// comment formats text as a line comment, e.g. a doc comment:
+//
+//	{{comment .Doc}}
+
+// FileData is the data of the "file" template.
+type FileData struct {
+	Version string     // gomock version, see Version
+	Package string     // package name of the file
+	Imports []Import   // imports of the file ordered by path, including the ones of the import function
+	Mocks   []MockData // mocks of the file
+}
+
+// Import is an import of the generated file.
+type Import struct {
+	Name string `json:"name,omitempty"` // set if differs from the package name
+	Path string `json:"path"`
+}
+
+// MockData is the data of the "mock" template.
+// Types are qualified by the names of the file imports.
+type MockData struct {
+	Mock       string // mock type name
+	New        string // mock constructor name
+	MockPkg    string // name of the imported mock package
+	Iface      string // interface name, e.g. "Repo"
+	IfaceFull  string // interface type used in method expressions, e.g. "storage.Repo[T]"
+	IfacePath  string // import path of the interface package; empty for the built-in error interface
+	IfacePkg   string // name of the imported interface package; empty if it is the package of the file or the interface is inline
+	Source     string // fully qualified interface, e.g. "github.com/someone/storage.Repo[github.com/someone/storage.User]"
+	Doc        string // doc comment text of the interface, e.g. "Storage stores values."
+	TypeParams string // type parameters of generic mock, e.g. "[T any]"
+	TypeArgs   string // type arguments of generic mock usage, e.g. "[T]"
+	Methods    []Func // methods of the interface including the embedded ones
+	FuncType   bool   // the mock is of a function type; Methods is its signature named "Fn"
+
+	// Names of the recorder type and of the call types by method names,
+	// e.g. "mockRepoRecorder" and "Get": "mockRepoGetCall", distinct from
+	// the other declarations of the package.
+	Recorder string
+	Calls    map[string]string
+
+	// Names of receivers of the mock, recorder and call types
+	// and of the constructor parameters.
+	Recv, RecorderRecv, CallRecv string
+	CtorT, CtorOpts              string
+
+	// Names of the recorder fields holding the mock and the declaring function,
+	// e.g. "m" and "declare", distinct from the method names.
+	RecorderMock, RecorderDeclare string
+
+	// The interface implementation is asserted at compile time by
+	// var _ AssertIface = (*Mock AssertArgs)(nil).
+	AssertIface string // e.g. "pkg.Repo[int]"; empty if there are no suitable type arguments
+	AssertArgs  string // sample type arguments of generic mock, e.g. "[int]"
+}
+
+// Func represents a function signature.
+type Func struct {
+	Name     string  `json:"name"`
+	Doc      string  `json:"doc,omitempty"` // doc comment text of the method
+	Params   []Param `json:"params"`
+	Res      []Param `json:"results"`
+	Variadic bool    `json:"variadic,omitempty"` // the last parameter is variadic
+}
+
+// Param represents a parameter in a function or method signature.
+// Unnamed parameters are named "in<N>" and results "out<N>" by their positions.
+type Param struct {
+	Name     string `json:"name,omitempty"`
+	Type     string `json:"type"` // e.g. "io.Reader" or "...int" for a variadic parameter
+	Variadic bool   `json:"variadic,omitempty"`
+}
+
+// ArgType returns the type of the argument passed to mock.Call.
+// It differs from Type for variadic parameters: "...int" => "[]int".
+func (p Param) ArgType() string {
+	if strings.HasPrefix(p.Type, "...") {
+		return "[]" + p.Type[len("..."):]
+	}
+	return p.Type
+}
+
+const fileTmplStr = `// Code generated by gomock {{.Version}}. DO NOT EDIT.
+// Interfaces:{{range .Mocks}}
+//	{{.Source}}{{end}}
+
+package {{.Package}}
+
+import (
+{{range .Imports}}	{{.Name}} "{{.Path}}"
+{{end}})
+{{range .Mocks}}{{if .FuncType}}{{template "func" .}}{{else}}{{template "mock" .}}{{end}}{{end}}`
+
+const mockTmplStr = `
+// {{.Mock}} is a mock of {{.IfaceFull}}.{{with .Doc}}
+//
+{{comment .}}{{end}}
+type {{.Mock}}{{.TypeParams}} struct {
+	{{.MockPkg}}.M
+}
+{{if .AssertIface}}
+var _ {{.AssertIface}} = (*{{.Mock}}{{.AssertArgs}})(nil)
+{{else}}
+// {{.Mock}} isn't asserted to implement {{.Iface}}: no type arguments satisfying its constraints are found
+{{end}}
+// {{.New}} returns a new {{.Mock}} which checks its expectations when the test finishes
+func {{.New}}{{.TypeParams}}({{.CtorT}} {{.MockPkg}}.TestingT, {{.CtorOpts}} ...{{.MockPkg}}.Option) *{{.Mock}}{{.TypeArgs}} {
+	return &{{.Mock}}{{.TypeArgs}}{ {{.MockPkg}}.New({{.CtorT}}, append([]{{.MockPkg}}.Option{ {{.MockPkg}}.CheckOnCleanup()}, {{.CtorOpts}}...)...)}
+}
+{{range .Methods}}
+{{with .Doc}}{{comment .}}
+{{end}}func ({{$.Recv}} *{{$.Mock}}{{$.TypeArgs}}) {{.Name}} ({{range .Params}}{{.Name}} {{.Type}}, {{end}}) ({{range .Res}}{{.Name}} {{.Type}}, {{end}}) {
+	{{$.MockPkg}}.Call({{$.Recv}}, {{$.IfaceFull}}.{{.Name}}, {{range .Params}}{{.Name}}, {{end}}).Return({{range .Res}}&{{.Name}}, {{end}})
+	return
+}
+{{end}}
+{{template "recorder" .}}`
+
+const funcTmplStr = `
+// {{.Mock}} is a mock of function type {{.IfaceFull}}.{{with .Doc}}
+//
+{{comment .}}{{end}}
+type {{.Mock}}{{.TypeParams}} struct {
+	{{.MockPkg}}.M
+}
+
+// {{.New}} returns a new {{.Mock}} which checks its expectations when the test finishes
+func {{.New}}{{.TypeParams}}({{.CtorT}} {{.MockPkg}}.TestingT, {{.CtorOpts}} ...{{.MockPkg}}.Option) *{{.Mock}}{{.TypeArgs}} {
+	return &{{.Mock}}{{.TypeArgs}}{ {{.MockPkg}}.New({{.CtorT}}, append([]{{.MockPkg}}.Option{ {{.MockPkg}}.CheckOnCleanup()}, {{.CtorOpts}}...)...)}
+}
+{{range .Methods}}
+// Fn returns a {{$.Iface}} function which calls are dispatched to the mock
+func ({{$.Recv}} *{{$.Mock}}{{$.TypeArgs}}) Fn() {{$.IfaceFull}} {
+	return func({{range .Params}}{{.Name}} {{.Type}}, {{end}}) ({{range .Res}}{{.Name}} {{.Type}}, {{end}}) {
+		{{$.MockPkg}}.Call({{$.Recv}}, {{$.IfaceFull}}(nil), {{range .Params}}{{.Name}}, {{end}}).Return({{range .Res}}&{{.Name}}, {{end}})
+		return
+	}
+}
+{{end}}
+{{template "recorder" .}}`
+
+// recorderTmplStr renders the typed recorders of mocks. Calls of a function type
+// are identified by the nil function of the type, e.g. "pkg.Clock(nil)".
+const recorderTmplStr = `// EXPECT returns the typed recorder of calls which must be made during the test
+func ({{.Recv}} *{{.Mock}}{{.TypeArgs}}) EXPECT() *{{.Recorder}}{{.TypeArgs}} {
+	return &{{.Recorder}}{{.TypeArgs}}{ {{.Recv}}, {{.MockPkg}}.ExpectCall}
+}
+
+// ON returns the typed recorder of calls which can be made during the test
+func ({{.Recv}} *{{.Mock}}{{.TypeArgs}}) ON() *{{.Recorder}}{{.TypeArgs}} {
+	return &{{.Recorder}}{{.TypeArgs}}{ {{.Recv}}, {{.MockPkg}}.OnCall}
+}
+
+type {{.Recorder}}{{.TypeParams}} struct {
+	{{.RecorderMock}} *{{.Mock}}{{.TypeArgs}}
+	{{.RecorderDeclare}} func(obj interface{}, f interface{}, args ...interface{}) {{.MockPkg}}.Returner
+}
+{{range .Methods}}
+func ({{$.RecorderRecv}} *{{$.Recorder}}{{$.TypeArgs}}) {{.Name}} ({{range .Params}}{{.Name}} {{$.MockPkg}}.Arg[{{.ArgType}}], {{end}}) {{index $.Calls .Name}}{{$.TypeArgs}} {
+	return {{index $.Calls .Name}}{{$.TypeArgs}}{ {{$.RecorderRecv}}.{{$.RecorderDeclare}}({{$.RecorderRecv}}.{{$.RecorderMock}}, {{if $.FuncType}}{{$.IfaceFull}}(nil){{else}}{{$.IfaceFull}}.{{.Name}}{{end}}, {{range .Params}}{{.Name}}, {{end}})}
+}
+
+type {{index $.Calls .Name}}{{$.TypeParams}} struct {
+	r {{$.MockPkg}}.Returner
+}
+{{if .Res}}
+func ({{$.CallRecv}} {{index $.Calls .Name}}{{$.TypeArgs}}) Return ({{range .Res}}{{.Name}} {{.Type}}, {{end}}) {
+	{{$.CallRecv}}.r.Return({{range .Res}}{{.Name}}, {{end}})
+}
+{{end}}{{end}}
+`
+
+// Template is a set of templates generating files, see FileData.
+type Template struct {
+	t *template.Template
+}
+
+// tmplFuncs are the functions of templates. The actual ones
+// are bound to the imports of the generated file by Template.execute.
+var tmplFuncs = template.FuncMap{
+	"import": func(path string, name ...string) (string, error) {
+		return "", fmt.Errorf("import %s: no generated file", path)
+	},
+	"comment": comment,
+}
+
+// comment returns text as line comments, empty for empty text.
+func comment(text string) string {
+	if text == "" {
+		return ""
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace("// " + line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+var defaultTemplate = func() *Template {
+	t := template.Must(template.New("file").Funcs(tmplFuncs).Parse(fileTmplStr))
+	template.Must(t.New("mock").Parse(mockTmplStr))
+	template.Must(t.New("func").Parse(funcTmplStr))
+	template.Must(t.New("recorder").Parse(recorderTmplStr))
+	return &Template{t: t}
+}()
+
+// ParseTemplate parses a custom template of mocks. text is the body of the "mock" template
+// executed with MockData per mock, including the mocks of function types unless text
+// redefines the "func" template; name is used in error messages, e.g. the file name.
+// text may also redefine the "file" template executed with FileData.
+func ParseTemplate(name string, text string) (*Template, error) {
+	t, err := defaultTemplate.t.Clone()
+	if err != nil {
+		return nil, err
+	}
+	template.Must(t.New("func").Parse(`{{template "mock" .}}`))
+	if _, err := t.New("mock").Parse(text); err != nil {
+		return nil, fmt.Errorf("%s: %v", name, strings.TrimPrefix(err.Error(), "template: "))
+	}
+	return &Template{t: t}, nil
+}
+
+// execute renders the file data. Packages imported by templates are added to imps.
+func (t *Template) execute(data FileData, imps *importSet) ([]byte, error) {
+	tmpl, err := t.t.Clone()
+	if err != nil {
+		return nil, err
+	}
+	tmpl.Funcs(template.FuncMap{
+		"import": func(p string, name ...string) (string, error) {
+			if len(name) > 1 {
+				return "", fmt.Errorf("import %s: too many arguments", p)
+			}
+			if len(name) == 0 {
+				name = []string{importName(p)}
+			}
+			return imps.add(p, name[0]), nil
+		},
+	})
+
+	// The imports are listed before the mocks adding them, so the file is rendered twice.
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "file", data); err != nil {
+		return nil, err
+	}
+	data.Imports = imps.sorted()
+	buf.Reset()
+	if err := tmpl.ExecuteTemplate(&buf, "file", data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// importName returns the default name of the package with the import path p:
+// its last element skipping a major version suffix, e.g. "yaml" for "gopkg.in/yaml.v3".
+func importName(p string) string {
+	name := path.Base(p)
+	if isMajorVersion(name) && path.Dir(p) != "." {
+		name = path.Base(path.Dir(p))
+	}
+	if dot := strings.Index(name, "."); dot > 0 {
+		name = name[:dot]
+	}
+	return strings.ReplaceAll(strings.TrimPrefix(name, "go-"), "-", "_")
+}
+
+// isMajorVersion reports whether elem is a major version path element like "v2".
+func isMajorVersion(elem string) bool {
+	if len(elem) < 2 || elem[0] != 'v' {
+		return false
+	}
+	for _, r := range elem[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,18 @@
+// Package bad has interfaces referring to undefined types.
+package bad
+
+// Getter gets values of an undefined type.
+type Getter interface {
+	Get(key string) Undefined
+}
+
+// Setter is valid despite the type errors of the package.
+type Setter interface {
+	Set(key string, v int)
+}
+
+// Store embeds the invalid Getter.
+type Store interface {
+	Getter
+	Setter
+}
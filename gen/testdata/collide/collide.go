@@ -0,0 +1,47 @@
+// Package collide has interfaces which identifiers collide with the ones of the generated mocks.
+package collide
+
+import (
+	"io"
+
+	mk "github.com/unkeep/gomock/gen/testdata/collide/mock"
+)
+
+// mock collides with the mocking engine package name
+// if the mocks are generated into this package.
+var mock = "mock"
+
+// Params has parameters named after receivers, packages and generated names.
+type Params interface {
+	Receivers(m int, r string, c bool) (mock mk.T, out1 error)
+	Unnamed(_ int, in1 string, _ io.Reader) (in2, _ int)
+	Packages(io io.Reader, collide int, mk mk.T) Params
+	Variadic(t string, opts ...mk.T)
+	Types(mockParamsRecorder int, mockParamsTypesCall string, append bool)
+}
+
+// Generic has type parameters named after receivers and constructor parameters.
+type Generic[t any, m comparable] interface {
+	Get(t t, r m) (c t)
+}
+
+// Fields has methods named after the fields of the generated recorder.
+// It can be mocked in this package only.
+type Fields interface {
+	m()
+	declare(int)
+}
+
+// Store is mocked along with StoreRecorder named after its recorder.
+type Store interface {
+	Get(key string) string
+}
+
+// StoreRecorder records stores.
+type StoreRecorder interface {
+	Record(s Store)
+}
+
+// mockStoreGetCall collides with the call type of the Store mock
+// if the mocks are generated into this package.
+type mockStoreGetCall struct{}
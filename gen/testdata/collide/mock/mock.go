@@ -0,0 +1,5 @@
+// Package mock has the same name as the mocking engine package.
+package mock
+
+// T is a type of a package named mock.
+type T int
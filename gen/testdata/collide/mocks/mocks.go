@@ -0,0 +1,2 @@
+// Package mocks is the package of the collide_mocks.golden mocks.
+package mocks
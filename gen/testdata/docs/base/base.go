@@ -0,0 +1,9 @@
+// Package base declares interfaces embedded by the docs package.
+package base
+
+// Closer is embedded by docs.Logger.
+type Closer interface {
+	// Close flushes the messages
+	// and closes the output.
+	Close() error
+}
@@ -0,0 +1,18 @@
+// Package docs declares interfaces with doc comments.
+package docs
+
+import "github.com/unkeep/gomock/gen/testdata/docs/base"
+
+// Logger logs messages.
+//
+// It is safe for concurrent use.
+//
+//gomock:generate
+type Logger interface {
+	// Log logs the message formatted
+	// by fmt.Sprintf.
+	Log(log string, args ...interface{}) error
+	Level() int // not a doc comment
+
+	base.Closer
+}
@@ -0,0 +1,28 @@
+// Package extract declares concrete types to extract interfaces from.
+package extract
+
+import (
+	"context"
+	"io"
+)
+
+type conn struct{}
+
+// Close closes the connection.
+func (conn) Close() error { return nil }
+
+// Client is a concrete client.
+type Client struct {
+	conn
+}
+
+// Get returns the value of the key.
+func (c *Client) Get(ctx context.Context, key string) ([]byte, error) { return nil, nil }
+
+// Put stores the value read from r.
+func (c *Client) Put(ctx context.Context, key string, r io.Reader, opts ...Option) error { return nil }
+
+func (c *Client) reset() {}
+
+// Option is an option of Client.Put.
+type Option func()
@@ -0,0 +1,17 @@
+// Package funcs declares function types.
+package funcs
+
+import (
+	"context"
+	"time"
+)
+
+// Clock returns the current time.
+//
+//gomock:generate out=mock_gen.go
+type Clock func() time.Time
+
+// Handler handles requests.
+//
+//gomock:generate out=mock_gen.go
+type Handler[T any] func(ctx context.Context, req T, opts ...string) (int, error)
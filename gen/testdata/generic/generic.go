@@ -0,0 +1,40 @@
+// Package generic has generic interfaces mocked along with their instantiations.
+package generic
+
+// User is a stored user.
+type User struct {
+	Name string
+}
+
+// Repo stores entities.
+type Repo[T any] interface {
+	Get(id int) (T, error)
+	Put(id int, v T) error
+}
+
+// Cache caches values by keys.
+type Cache[K comparable, V any] interface {
+	Load(key K) (V, bool)
+	Store(key K, v V)
+}
+
+// Number is a numeric constraint.
+type Number interface {
+	~int64 | ~float64
+}
+
+// Summer sums numbers.
+type Summer[T Number] interface {
+	Sum(vs ...T) T
+}
+
+// Stringer is a constraint no predeclared type satisfies.
+type Stringer interface {
+	~int
+	String() string
+}
+
+// Printer prints values having a String method.
+type Printer[T Stringer] interface {
+	Print(v T)
+}
@@ -0,0 +1,2 @@
+// Package fakes is the package of the generated imports mocks.
+package fakes
@@ -0,0 +1,18 @@
+// Package imports has interfaces referring to packages with colliding or unusual names.
+package imports
+
+import (
+	htmpl "html/template"
+	"io"
+	"net/http"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Renderer renders templates.
+type Renderer interface {
+	Text(t *template.Template, w io.Writer) error
+	HTML(t *htmpl.Template, w http.ResponseWriter) error
+	Node(n *yaml.Node) (yaml.Kind, error)
+}
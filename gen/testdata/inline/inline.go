@@ -0,0 +1,26 @@
+// Package inline declares inline interfaces.
+package inline
+
+import "context"
+
+// Item is a stored item.
+type Item struct{}
+
+// Service serves items.
+type Service struct {
+	deps struct {
+		// store stores the items.
+		store interface {
+			// Get returns the item by its key.
+			Get(ctx context.Context, key string) (*Item, error)
+			Put(key string, item Item) error
+		}
+	}
+	logs []interface{ Log(msg string) }
+}
+
+// Do does the job.
+func (s *Service) Do(cache interface{ Len() int }) {}
+
+// Handle handles a request.
+func Handle(ctx context.Context, notifier interface{ Notify(...string) }) {}
@@ -0,0 +1,21 @@
+// Package methods has interfaces embedding interfaces of packages imported under other names.
+package methods
+
+import (
+	stdio "io"
+	. "net/http"
+)
+
+// Body embeds interfaces with overlapping methods.
+type Body interface {
+	stdio.ReadCloser
+	stdio.ReadWriter
+	Close() error
+	WriteTo(w stdio.Writer) (int64, error)
+}
+
+// Service refers to the types of a dot import.
+type Service interface {
+	Handler
+	Serve(w ResponseWriter, r *Request) error
+}
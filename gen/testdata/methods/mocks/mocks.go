@@ -0,0 +1,2 @@
+// Package mocks is the package of the generated methods mocks.
+package mocks
@@ -0,0 +1,9 @@
+// Package syntax has a syntax error.
+package syntax
+
+// Broken is followed by a statement outside of a function body.
+type Broken interface {
+	Do(key string)
+}
+
+Broken.Do()
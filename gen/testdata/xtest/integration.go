@@ -0,0 +1,8 @@
+//go:build integration
+
+package xtest
+
+// Remote is a remote store used by integration tests.
+type Remote interface {
+	Fetch(key string) (Value, error)
+}
@@ -0,0 +1,6 @@
+package xtest
+
+// Store stores values in tests.
+type Store interface {
+	Get(key string) (Value, error)
+}
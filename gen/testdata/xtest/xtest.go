@@ -0,0 +1,7 @@
+// Package xtest has interfaces declared by test files and files behind build constraints.
+package xtest
+
+// Value is a value stored by Store.
+type Value struct {
+	Data string
+}
@@ -0,0 +1,16 @@
+package xtest_test
+
+import "github.com/unkeep/gomock/gen/testdata/xtest"
+
+// Cache caches values in external tests.
+type Cache interface {
+	Put(key string, v xtest.Value)
+}
+
+// Local is a type of the external test package.
+type Local struct{}
+
+// Loader loads the types of the external test package.
+type Loader interface {
+	Load() Local
+}
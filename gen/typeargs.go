@@ -0,0 +1,148 @@
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// splitTypeArgs splits an instantiated interface name into the interface
+// name and the type arguments.
+// For example, given "pkg.Repo[pkg.User, int]", splitTypeArgs returns
+// "pkg.Repo", ["pkg.User", "int"].
+func splitTypeArgs(iface string) (string, []string, error) {
+	open := strings.Index(iface, "[")
+	if open == -1 {
+		return iface, nil, nil
+	}
+	if !strings.HasSuffix(iface, "]") {
+		return "", nil, fmt.Errorf("invalid type arguments: %s", iface)
+	}
+
+	var args []string
+	depth, start := 0, open+1
+	for i := start; i < len(iface)-1; i++ {
+		switch iface[i] {
+		case '[', '(', '{':
+			depth++
+		case ']', ')', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, strings.TrimSpace(iface[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	args = append(args, strings.TrimSpace(iface[start:len(iface)-1]))
+
+	for _, arg := range args {
+		if arg == "" {
+			return "", nil, fmt.Errorf("invalid type arguments: %s", iface)
+		}
+	}
+	return iface[:open], args, nil
+}
+
+// typeArg resolves the type argument expression arg.
+// Unqualified named types are looked up in scope, the scope of
+// the generic interface package.
+// Named types from other packages are given the same way as interfaces:
+// "pkg.User" or "github.com/someone/pkg.User".
+func (l *Loader) typeArg(arg string, scope *types.Scope) (types.Type, error) {
+	if strings.Contains(arg, "/") {
+		return l.namedType(arg)
+	}
+
+	e, err := parser.ParseExpr(arg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid type argument %s: %v", arg, err)
+	}
+	return l.typeExpr(e, scope)
+}
+
+func (l *Loader) typeExpr(e ast.Expr, scope *types.Scope) (types.Type, error) {
+	switch e := e.(type) {
+	case *ast.Ident:
+		_, obj := scope.LookupParent(e.Name, token.NoPos)
+		if tn, ok := obj.(*types.TypeName); ok {
+			return tn.Type(), nil
+		}
+		return nil, fmt.Errorf("type %s not found", e.Name)
+	case *ast.SelectorExpr:
+		if x, ok := e.X.(*ast.Ident); ok {
+			return l.namedType(x.Name + "." + e.Sel.Name)
+		}
+	case *ast.StarExpr:
+		elem, err := l.typeExpr(e.X, scope)
+		if err != nil {
+			return nil, err
+		}
+		return types.NewPointer(elem), nil
+	case *ast.ArrayType:
+		if e.Len != nil {
+			break
+		}
+		elem, err := l.typeExpr(e.Elt, scope)
+		if err != nil {
+			return nil, err
+		}
+		return types.NewSlice(elem), nil
+	case *ast.MapType:
+		key, err := l.typeExpr(e.Key, scope)
+		if err != nil {
+			return nil, err
+		}
+		elem, err := l.typeExpr(e.Value, scope)
+		if err != nil {
+			return nil, err
+		}
+		return types.NewMap(key, elem), nil
+	}
+	return nil, fmt.Errorf("unsupported type argument: %T", e)
+}
+
+// namedType resolves a package qualified type name.
+func (l *Loader) namedType(name string) (types.Type, error) {
+	path, id, err := l.findInterface(name)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := l.loadPkg(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tn, ok := p.Types.Scope().Lookup(id).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("type %s not found in %s", id, path)
+	}
+	return tn.Type(), nil
+}
+
+// instantiate instantiates the generic type typ with type arguments args.
+func (l *Loader) instantiate(typ types.Type, args []string, scope *types.Scope) (types.Type, error) {
+	named, ok := typ.(*types.Named)
+	if !ok || named.TypeParams().Len() == 0 {
+		return nil, fmt.Errorf("%s is not generic", typ)
+	}
+	if named.TypeParams().Len() != len(args) {
+		return nil, fmt.Errorf("%s: got %d type arguments, expected %d",
+			typ, len(args), named.TypeParams().Len())
+	}
+
+	targs := make([]types.Type, len(args))
+	for i, arg := range args {
+		t, err := l.typeArg(arg, scope)
+		if err != nil {
+			return nil, err
+		}
+		targs[i] = t
+	}
+
+	return types.Instantiate(nil, named, targs, true)
+}
@@ -0,0 +1,15 @@
+module github.com/unkeep/gomock
+
+go 1.22.0
+
+toolchain go1.22.12
+
+require (
+	golang.org/x/tools v0.30.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	golang.org/x/mod v0.23.0 // indirect
+	golang.org/x/sync v0.11.0 // indirect
+)
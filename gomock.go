@@ -1,355 +1,290 @@
 package main
 
 import (
-	"bytes"
 	"flag"
 	"fmt"
-	"go/ast"
-	"go/build"
-	"go/format"
-	"go/parser"
-	"go/printer"
-	"go/token"
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
-	"text/template"
 
-	"golang.org/x/tools/imports"
+	"github.com/unkeep/gomock/gen"
 )
 
-const usage = `gomock <iface>
-
-gomock generates mocks for the given iface.
+const usage = `gomock [options] <iface>
+gomock [options] <packages>
+gomock generate [-config <file>]
+gomock check [-config <file>]
+gomock describe [-format json] <iface|packages>...
+gomock extract [-methods <A,B>] [-name <iface>] [-o <file>] [-mock <file>] <type>
+
+gomock generates mocks for the given iface, an interface or a named function type.
+An interface declared inline, e.g. by a struct field or a function parameter,
+is given by its path, e.g. pkg.Service.store, or position, e.g. service.go:12[:col].
+The mock of a function type has a method Fn() returning a function of the type
+which dispatches its calls to the mock.
+Given package patterns (e.g. ./... or the directory ./pkg, while ./pkg.Iface is
+an interface), gomock generates mocks for every interface
+annotated with a "//gomock:generate [name=<mock>] [out=<file>] [package=<name>]"
+comment, by default into "mock_<iface>_test.go" next to the interface.
+"gomock generate" generates every mock described by the config file,
+gomock.yaml, gomock.yml or .gomock.json in the working directory by default.
+"gomock check" regenerates the mocks described by the config file in memory
+and fails with a unified diff if they differ from the files on disk.
+"gomock describe" prints the method sets of the interfaces as a JSON array:
+methods with their parameter and result types, variadic flags and positions,
+and the import paths of the types.
+"gomock extract" declares an interface with the exported methods of the type,
+or the -methods subset, named <type>API by default. The interface is written
+to stdout or -o, and -mock also generates its mock into the given file.
+The options before the command, e.g. -exported, apply to the mock.
+
+Options:
+
+-package <name>     package name of the generated file. Defaults to the package
+                    of the destination directory or "mocks"
+-destination <file> write the generated file to <file> instead of stdout
+-o <file>           shorthand for -destination
+
+-all                generate mocks for every exported interface of the packages
+                    given as arguments (package patterns, e.g. ./pkg/storage or ./...).
+                    A file "mock_<package>_test.go" is generated per package,
+                    -destination generates all the mocks into a single file
+-include <regexp>   generate mocks only for interfaces with matching names
+-exclude <regexp>   skip interfaces with matching names
+-per-iface          generate a file "mock_<iface>_test.go" per interface
+-dir <dir>          write the files to <dir> instead of the package directories
+
+-name <pattern>     mock type name pattern, e.g. "Fake{{.Iface}}". Defaults to "mock{{.Iface}}"
+-exported           export mock type names, e.g. MockReader. Every mock has a constructor
+                    named after it, e.g. NewMockReader, checking expectations on test cleanup
+-template <file>    render mocks by the text/template in <file> instead of the default one.
+                    It renders the mocks of function types too, unless it defines
+                    a "func" template. See the data model in the documentation of
+                    the gen package
+
+-tags <tags>        comma-separated build tags of the loaded packages, e.g. integration
+-goos <os>          GOOS of the loaded packages, e.g. windows
+-goarch <arch>      GOARCH of the loaded packages, e.g. arm64
+-include-tests      load _test.go files, so interfaces declared in tests can be mocked.
+                    Interfaces of external test packages are named <path>_test.<iface>
+
+-v                  explain how interfaces are resolved: import paths, directories
+                    and files scanned
+
+-verify             check that the files are up to date instead of writing them.
+                    Prints a unified diff and fails if they are not. The gomock
+                    version in the header of the files isn't compared
 
 Examples:
 
 gomock io.Reader
 gomock somepkg.SomeInterface
-gomock github.com/unkeep/somepkg.SomeInterface
+gomock -package mocks -o mocks/reader.go github.com/unkeep/somepkg.SomeInterface
+gomock somepkg.GenericInterface
+gomock 'somepkg.GenericInterface[somepkg.SomeType, int]'
+gomock somepkg.SomeFuncType
+gomock somepkg.SomeStruct.someField
+gomock -o mocks/store.go ./service.go:12
+gomock -all -exclude 'Internal$' ./pkg/storage ./pkg/cache
+gomock ./...
+gomock -include-tests -tags integration ./...
+gomock -goos windows generate
+gomock describe -format=json io.ReadWriter
+gomock extract -methods Do,Get -name ClientAPI -o client_api.go -mock mock_client_test.go net/http.Client
 `
 
-// findInterface returns the import path and identifier of an interface.
-// For example, given "http.ResponseWriter", findInterface returns
-// "net/http", "ResponseWriter".
-// If a fully qualified interface is given, such as "net/http.ResponseWriter",
-// it simply parses the input.
-func findInterface(iface string, srcDir string) (path string, id string, err error) {
-	if len(strings.Fields(iface)) != 1 {
-		return "", "", fmt.Errorf("couldn't parse interface: %s", iface)
-	}
+// buildOpts are the build options of all loaded packages set by the flags.
+var buildOpts gen.Loader
 
-	srcPath := filepath.Join(srcDir, "__go_impl__.go")
+// newLoader returns a loader with the build options resolving interfaces in dir.
+func newLoader(dir string) *gen.Loader {
+	l := buildOpts
+	l.Dir = dir
+	return &l
+}
 
-	if slash := strings.LastIndex(iface, "/"); slash > -1 {
-		// package path provided
-		dot := strings.LastIndex(iface, ".")
-		// make sure iface does not end with "/" (e.g. reject net/http/)
-		if slash+1 == len(iface) {
-			return "", "", fmt.Errorf("interface name cannot end with a '/' character: %s", iface)
-		}
-		// make sure iface does not end with "." (e.g. reject net/http.)
-		if dot+1 == len(iface) {
-			return "", "", fmt.Errorf("interface name cannot end with a '.' character: %s", iface)
-		}
-		// make sure iface has exactly one "." after "/" (e.g. reject net/http/httputil)
-		if strings.Count(iface[slash:], ".") != 1 {
-			return "", "", fmt.Errorf("invalid interface name: %s", iface)
-		}
-		return iface[:dot], iface[dot+1:], nil
+func main() {
+	pkgName := flag.String("package", "", "package name of the generated file")
+	dest := flag.String("destination", "", "output file; defaults to stdout")
+	flag.StringVar(dest, "o", "", "shorthand for -destination")
+	flag.StringVar(&buildOpts.Tags, "tags", "", "comma-separated build tags of the loaded packages")
+	flag.StringVar(&buildOpts.GOOS, "goos", "", "GOOS of the loaded packages")
+	flag.StringVar(&buildOpts.GOARCH, "goarch", "", "GOARCH of the loaded packages")
+	flag.BoolVar(&buildOpts.Tests, "include-tests", false, "load _test.go files and external test packages")
+	verbose := flag.Bool("v", false, "explain how interfaces are resolved")
+	all := flag.Bool("all", false, "generate mocks for every exported interface of the packages")
+	include := flag.String("include", "", "regexp of interface names to generate mocks for with -all")
+	exclude := flag.String("exclude", "", "regexp of interface names to skip with -all")
+	outDir := flag.String("dir", "", "output directory with -all; defaults to the package directories")
+	perIface := flag.Bool("per-iface", false, "generate a file per interface with -all")
+	verify := flag.Bool("verify", false, "check that the generated files are up to date instead of writing them")
+	namePattern := flag.String("name", "", `mock type name pattern; defaults to "mock{{.Iface}}"`)
+	exported := flag.Bool("exported", false, "export mock type names")
+	tmplPath := flag.String("template", "", "custom mock template file")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
 	}
+	flag.Parse()
 
-	src := []byte("package hack\n" + "var i " + iface)
-	// If we couldn't determine the import path, goimports will
-	// auto fix the import path.
-	imp, err := imports.Process(srcPath, src, nil)
-	if err != nil {
-		return "", "", fmt.Errorf("couldn't parse interface: %s", iface)
+	if len(flag.Args()) < 1 {
+		flag.Usage()
+		os.Exit(2)
 	}
 
-	// imp should now contain an appropriate import.
-	// Parse out the import and the identifier.
-	fset := token.NewFileSet()
-	f, err := parser.ParseFile(fset, srcPath, imp, 0)
-	if err != nil {
-		panic(err)
-	}
-	if len(f.Imports) == 0 {
-		return "", "", fmt.Errorf("unrecognized interface: %s", iface)
-	}
-	raw := f.Imports[0].Path.Value   // "io"
-	path, err = strconv.Unquote(raw) // io
-	if err != nil {
-		panic(err)
+	if *verbose {
+		buildOpts.Logf = func(format string, args ...interface{}) {
+			fmt.Fprintf(os.Stderr, format+"\n", args...)
+		}
 	}
-	decl := f.Decls[1].(*ast.GenDecl)      // var i io.Reader
-	spec := decl.Specs[0].(*ast.ValueSpec) // i io.Reader
-	sel := spec.Type.(*ast.SelectorExpr)   // io.Reader
-	id = sel.Sel.Name                      // Reader
-	return path, id, nil
-}
-
-// Pkg is a parsed build.Package.
-type Pkg struct {
-	*build.Package
-	*token.FileSet
-}
 
-// typeSpec locates the *ast.TypeSpec for type id in the import path.
-func typeSpec(path string, id string, srcDir string) (Pkg, *ast.TypeSpec, error) {
-	pkg, err := build.Import(path, srcDir, 0)
-	if err != nil {
-		return Pkg{}, nil, fmt.Errorf("couldn't find package %s: %v", path, err)
+	opts := gen.Options{Package: *pkgName, NamePattern: *namePattern, Exported: *exported}
+	if *tmplPath != "" {
+		var err error
+		if opts.Template, err = readTemplate(*tmplPath); err != nil {
+			fatal(err)
+		}
 	}
 
-	fset := token.NewFileSet() // share one fset across the whole package
-	for _, file := range pkg.GoFiles {
-		f, err := parser.ParseFile(fset, filepath.Join(pkg.Dir, file), nil, 0)
-		if err != nil {
-			continue
+	switch flag.Arg(0) {
+	case "generate":
+		if err := generate(flag.Args()[1:]); err != nil {
+			fatal(err)
 		}
-
-		for _, decl := range f.Decls {
-			decl, ok := decl.(*ast.GenDecl)
-			if !ok || decl.Tok != token.TYPE {
-				continue
-			}
-			for _, spec := range decl.Specs {
-				spec := spec.(*ast.TypeSpec)
-				if spec.Name.Name != id {
-					continue
-				}
-				return Pkg{Package: pkg, FileSet: fset}, spec, nil
-			}
+		return
+	case "check":
+		if err := check(flag.Args()[1:]); err != nil {
+			fatal(err)
 		}
-	}
-	return Pkg{}, nil, fmt.Errorf("type %s not found in %s", id, path)
-}
-
-// gofmt pretty-prints e.
-func (p Pkg) gofmt(e ast.Expr) string {
-	var buf bytes.Buffer
-	printer.Fprint(&buf, p.FileSet, e)
-	return buf.String()
-}
-
-// fullType returns the fully qualified type of e.
-// Examples, assuming package net/http:
-// 	fullType(int) => "int"
-// 	fullType(Handler) => "http.Handler"
-// 	fullType(io.Reader) => "io.Reader"
-// 	fullType(*Request) => "*http.Request"
-func (p Pkg) fullType(e ast.Expr) string {
-	ast.Inspect(e, func(n ast.Node) bool {
-		switch n := n.(type) {
-		case *ast.Ident:
-			// Using typeSpec instead of IsExported here would be
-			// more accurate, but it'd be crazy expensive, and if
-			// the type isn't exported, there's no point trying
-			// to implement it anyway.
-			if n.IsExported() {
-				n.Name = p.Package.Name + "." + n.Name
-			}
-		case *ast.SelectorExpr:
-			return false
+		return
+	case "extract":
+		if err := extract(flag.Args()[1:], opts); err != nil {
+			fatal(err)
 		}
-		return true
-	})
-	return p.gofmt(e)
-}
-
-func (p Pkg) params(field *ast.Field) []Param {
-	var params []Param
-	typ := p.fullType(field.Type)
-	for _, name := range field.Names {
-		params = append(params, Param{Name: name.Name, Type: typ})
-	}
-	// Handle anonymous params
-	if len(params) == 0 {
-		params = []Param{Param{Type: typ}}
+		return
+	case "describe":
+		if err := describe(flag.Args()[1:]); err != nil {
+			fatal(err)
+		}
+		return
 	}
-	return params
-}
 
-// Method represents a method signature.
-type Method struct {
-	Recv string
-	Func
-}
+	wd, _ := os.Getwd()
 
-// Func represents a function signature.
-type Func struct {
-	Name   string
-	Params []Param
-	Res    []Param
-}
+	if *all {
+		filter, err := newIfaceFilter(*include, *exclude)
+		if err != nil {
+			fatal(err)
+		}
 
-// Param represents a parameter in a function or method signature.
-type Param struct {
-	Name string
-	Type string
-}
+		ifaces, err := newLoader(wd).LoadPackages(flag.Args()...)
+		if err != nil {
+			fatal(err)
+		}
 
-func (p Pkg) funcsig(f *ast.Field) Func {
-	fn := Func{Name: f.Names[0].Name}
-	typ := f.Type.(*ast.FuncType)
-	if typ.Params != nil {
-		for _, field := range typ.Params.List {
-			fn.Params = append(fn.Params, p.params(field)...)
+		if *dest != "" && (*outDir != "" || *perIface) {
+			fatal("-destination can't be combined with -dir or -per-iface")
 		}
-	}
-	if typ.Results != nil {
-		for _, field := range typ.Results.List {
-			fn.Res = append(fn.Res, p.params(field)...)
+		opts.Out, opts.Dir, opts.PerInterface = *dest, *outDir, *perIface
+		placed, err := gen.Place(filter.filter(ifaces), opts)
+		if err != nil {
+			fatal(err)
 		}
+		writeFiles(mockable(placed), opts, *verify)
+		return
 	}
 
-	addParamNames(&fn)
-
-	return fn
-}
-
-// The error interface is built-in.
-var errorInterface = []Func{{
-	Name: "Error",
-	Res:  []Param{{Type: "string"}},
-}}
-
-// funcs returns the set of methods required to implement iface.
-// It is called funcs rather than methods because the
-// function descriptions are functions; there is no receiver.
-func funcs(iface string, srcDir string) ([]Func, error) {
-	// Special case for the built-in error interface.
-	if iface == "error" {
-		return errorInterface, nil
+	if l := newLoader(wd); l.IsPackagePattern(flag.Arg(0)) {
+		if *dest != "" {
+			fatal("-destination can't be used with package patterns, set out in the //gomock:generate annotations instead")
+		}
+		ifaces, err := l.LoadAnnotated(flag.Args()...)
+		if err != nil {
+			fatal(err)
+		}
+		writeFiles(ifaces, opts, *verify)
+		return
 	}
 
-	// Locate the interface.
-	path, id, err := findInterface(iface, srcDir)
-	if err != nil {
-		return nil, err
+	if *dest == "" {
+		if *verify {
+			fatal("-verify requires -destination")
+		}
+		// The mock printed to stdout is generated into the package of the working directory.
+		opts.Out = filepath.Join(wd, "mock_stdout.go")
+	} else {
+		opts.Out = *dest
 	}
 
-	// Parse the package and find the interface declaration.
-	p, spec, err := typeSpec(path, id, srcDir)
-	if err != nil {
-		return nil, fmt.Errorf("interface %s not found: %s", iface, err)
+	iface := flag.Arg(0)
+	if file, pos, ok := strings.Cut(iface, ".go:"); ok && !filepath.IsAbs(file) {
+		// The position of an inline interface is relative to the working directory.
+		iface = filepath.Join(wd, file) + ".go:" + pos
 	}
-	idecl, ok := spec.Type.(*ast.InterfaceType)
-	if !ok {
-		return nil, fmt.Errorf("not an interface: %s", iface)
+	l, err := ifaceLoader(opts.Out)
+	if err != nil {
+		fatal(err)
 	}
-
-	if idecl.Methods == nil {
-		return nil, fmt.Errorf("empty interface: %s", iface)
+	ifaces, err := l.Load(iface)
+	if err != nil {
+		fatal(err)
 	}
 
-	var fns []Func
-	for _, fndecl := range idecl.Methods.List {
-		if len(fndecl.Names) == 0 {
-			// Embedded interface: recurse
-			embedded, err := funcs(p.fullType(fndecl.Type), srcDir)
-			if err != nil {
-				return nil, err
-			}
-			fns = append(fns, embedded...)
-			continue
+	if *dest == "" {
+		files, err := gen.Generate(ifaces, opts)
+		if err != nil {
+			fatal(err)
 		}
-
-		fn := p.funcsig(fndecl)
-		fns = append(fns, fn)
-	}
-	return fns, nil
-}
-
-const mockTmplStr = `
-type mock{{.Iface}} struct {
-	mock.M
-}
-{{ $data := .}}
-{{range .Methods}}
-func (m *mock{{$data.Iface}}) {{.Name}} ({{range .Params}}{{.Name}} {{.Type}}, {{end}}) ({{range .Res}}{{.Name}} {{.Type}}, {{end}}) {
-	mock.Call(m, {{$data.IfaceFull}}.{{.Name}}, {{range .Params}}{{.Name}}, {{end}}).Return({{range .Res}}&{{.Name}}, {{end}})
-	return
-}
-{{end}}
-`
-
-var tmpl = template.Must(template.New("test").Parse(mockTmplStr))
-
-type mockTmplData struct {
-	Iface     string
-	IfaceFull string
-	Methods   []Func
-}
-
-func getIfaceFull(iface string) string {
-	slashPos := strings.LastIndex(iface, "/")
-	if slashPos == -1 {
-		return iface
+		if _, err := os.Stdout.Write(files[0].Content); err != nil {
+			fatal(err)
+		}
+		return
 	}
-
-	return iface[slashPos+1:]
-
+	writeFiles(ifaces, opts, *verify)
 }
 
-func getIfaceShort(iface string) string {
-	tokens := strings.Split(iface, ".")
-	return tokens[len(tokens)-1]
-}
-
-// genMock prints nicely formatted mock implementation
-func genMock(iface string, fns []Func) []byte {
-	var buf bytes.Buffer
-	tmplData := mockTmplData{
-		Iface:     getIfaceShort(iface),
-		IfaceFull: getIfaceFull(iface),
-		Methods:   fns,
-	}
-	tmpl.Execute(&buf, tmplData)
-
-	pretty, err := format.Source(buf.Bytes())
+// ifaceLoader returns the loader of the interface to generate the out file for.
+func ifaceLoader(out string) (*gen.Loader, error) {
+	wd, _ := os.Getwd()
+	dir, err := ifaceDir(out, wd)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	return pretty
+	return newLoader(dir), nil
 }
 
-func addParamNames(f *Func) {
-	for i, p := range f.Params {
-		if p.Name == "" {
-			f.Params[i].Name = fmt.Sprintf("in%d", i+1)
-		}
+// ifaceDir returns the directory to resolve the interface to generate the out file for in:
+// the out directory if it exists, dir otherwise.
+func ifaceDir(out, dir string) (string, error) {
+	outDir, err := filepath.Abs(filepath.Dir(out))
+	if err != nil {
+		return "", err
 	}
-
-	for i, p := range f.Res {
-		if p.Name == "" {
-			f.Res[i].Name = fmt.Sprintf("out%d", i+1)
-		}
+	if _, err := os.Stat(outDir); err != nil {
+		return dir, nil
 	}
+	return outDir, nil
 }
 
-func main() {
-	flag.Parse()
-
-	if len(flag.Args()) < 1 {
-		fmt.Fprint(os.Stderr, usage)
-		os.Exit(2)
-	}
-
-	iface := flag.Arg(0)
-
-	wdir, _ := os.Getwd()
-
-	fns, err := funcs(iface, wdir)
+// writeFiles generates the mocks of ifaces and writes the files or,
+// if verify is set, checks that they are up to date.
+func writeFiles(ifaces []gen.Interface, opts gen.Options, verify bool) {
+	files, err := gen.Generate(ifaces, opts)
 	if err != nil {
 		fatal(err)
 	}
 
-	src := genMock(iface, fns)
+	if verify {
+		if err := checkFiles(files); err != nil {
+			fatal(err)
+		}
+		return
+	}
 
-	fmt.Print(string(src))
+	for _, f := range files {
+		if err := writeFile(f); err != nil {
+			fatal(err)
+		}
+	}
 }
 
 func fatal(msg interface{}) {
@@ -0,0 +1,71 @@
+package mock
+
+import "strings"
+
+// Fixture is a named reusable bundle of OnCall/ExpectCall declarations.
+// It can be applied to any mock object and composed with other fixtures.
+// Declarations made by a fixture are reported with the fixture name on failures.
+//
+// Parameterised fixtures are plain functions returning a Fixture:
+//
+//	func storedValue(key string, val int) mock.Fixture {
+//		return mock.NewFixture("storedValue", func(obj interface{}) {
+//			mock.OnCall(obj, Storage.GetValue, key).Return(val, nil)
+//		})
+//	}
+type Fixture struct {
+	name  string
+	setup func(obj interface{})
+	parts []Fixture
+}
+
+// NewFixture creates a fixture named 'name' which performs 'setup' for an applied mock object
+func NewFixture(name string, setup func(obj interface{})) Fixture {
+	return Fixture{name: name, setup: setup}
+}
+
+// Compose creates a fixture named 'name' which applies 'fixtures' in the given order
+func Compose(name string, fixtures ...Fixture) Fixture {
+	return Fixture{name: name, parts: fixtures}
+}
+
+// Name returns the fixture name
+func (f Fixture) Name() string {
+	return f.name
+}
+
+// With returns a copy of the fixture which additionally applies 'others' after own declarations
+func (f Fixture) With(others ...Fixture) Fixture {
+	parts := make([]Fixture, 0, len(f.parts)+len(others))
+	parts = append(parts, f.parts...)
+	parts = append(parts, others...)
+	return Fixture{name: f.name, setup: f.setup, parts: parts}
+}
+
+// Apply makes the fixture declarations for 'obj' mock object
+func (f Fixture) Apply(obj interface{}) {
+	c := getCore(obj)
+	c.fixtures = append(c.fixtures, f.name)
+	defer func() {
+		c.fixtures = c.fixtures[:len(c.fixtures)-1]
+	}()
+
+	if f.setup != nil {
+		f.setup(obj)
+	}
+
+	for _, part := range f.parts {
+		part.Apply(obj)
+	}
+}
+
+// Apply applies 'fixtures' to 'obj' mock object in the given order
+func Apply(obj interface{}, fixtures ...Fixture) {
+	for _, f := range fixtures {
+		f.Apply(obj)
+	}
+}
+
+func (c *core) fixtureName() string {
+	return strings.Join(c.fixtures, "/")
+}
@@ -0,0 +1,78 @@
+package mock
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func doSmth2Returns(data string) Fixture {
+	return NewFixture("doSmth2Returns", func(obj interface{}) {
+		OnCall(obj, myInterface.doSmth2).Return(myType{data})
+	})
+}
+
+func TestFixtureApply(t *testing.T) {
+	f := doSmth2Returns("data")
+
+	for i := 0; i < 2; i++ {
+		obj := &myObj{New(t)}
+		f.Apply(obj)
+
+		if v := obj.doSmth2(); !reflect.DeepEqual(v, myType{"data"}) {
+			t.Fatal(`!reflect.DeepEqual(v, myType{"data"})`)
+		}
+	}
+}
+
+func TestFixtureCompose(t *testing.T) {
+	m := New(t)
+	obj := &myObj{m}
+
+	expectSlice := NewFixture("expectSlice", func(obj interface{}) {
+		ExpectCall(obj, myInterface.slice, []int{1}).Return([]int{2})
+	})
+	Apply(obj, Compose("all", doSmth2Returns("data"), expectSlice))
+
+	if v := obj.doSmth2(); !reflect.DeepEqual(v, myType{"data"}) {
+		t.Fatal(`!reflect.DeepEqual(v, myType{"data"})`)
+	}
+
+	if out := obj.slice([]int{1}); !reflect.DeepEqual(out, []int{2}) {
+		t.Fatal(`!reflect.DeepEqual(out, []int{2})`)
+	}
+
+	m.CheckExpectations()
+}
+
+func TestFixtureWith(t *testing.T) {
+	f := NewFixture("base", nil).With(doSmth2Returns("data"))
+	obj := &myObj{New(t)}
+	f.Apply(obj)
+
+	if f.Name() != "base" {
+		t.Fatalf(`f.Name() != "base": %s`, f.Name())
+	}
+
+	if v := obj.doSmth2(); !reflect.DeepEqual(v, myType{"data"}) {
+		t.Fatal(`!reflect.DeepEqual(v, myType{"data"})`)
+	}
+}
+
+func TestFixtureNameInFailure(t *testing.T) {
+	tm := new(tmock)
+	m := New(tm)
+	obj := &myObj{m}
+
+	happyPath := NewFixture("happyPath", func(obj interface{}) {
+		ExpectCall(obj, myInterface.doSmth, 1)
+	})
+	Compose("all", happyPath).Apply(obj)
+	ExpectCall(obj, myInterface.doSmth2)
+
+	m.CheckExpectations()
+
+	if len(tm.msgs) != 1 || !strings.Contains(tm.msgs[0], "(from fixture all/happyPath)") {
+		t.Fatalf("fixture name is not reported: %v", tm.msgs)
+	}
+}
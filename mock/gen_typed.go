@@ -0,0 +1,162 @@
+//go:build ignore
+
+// gen_typed generates typed_gen.go: typed declaration helpers for every
+// supported number of method arguments and results.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"strings"
+	"text/template"
+)
+
+const (
+	maxArgs    = 5
+	maxResults = 3
+)
+
+const typedTmplStr = `// Code generated by gen_typed.go. DO NOT EDIT.
+
+package mock
+{{range .}}
+// Decl{{.Suffix}} is a typed declaration of a method with {{.NumArgs}} argument(s) and {{.NumRes}} result(s)
+type Decl{{.Suffix}}{{.DeclTypeParams}} struct {
+	decl *callDeclaration
+}
+
+// On{{.Suffix}} is a typed version of OnCall for a method with {{.NumArgs}} argument(s) and {{.NumRes}} result(s)
+func On{{.Suffix}}[I{{.TypeParams}} any](obj interface{}, f func(I{{.Args}}){{.Results}}) Decl{{.Suffix}}{{.DeclTypeArgs}} {
+	return Decl{{.Suffix}}{{.DeclTypeArgs}}{declaration(OnCall(obj, f))}
+}
+
+// Expect{{.Suffix}} is a typed version of ExpectCall for a method with {{.NumArgs}} argument(s) and {{.NumRes}} result(s)
+func Expect{{.Suffix}}[I{{.TypeParams}} any](obj interface{}, f func(I{{.Args}}){{.Results}}) Decl{{.Suffix}}{{.DeclTypeArgs}} {
+	return Decl{{.Suffix}}{{.DeclTypeArgs}}{declaration(ExpectCall(obj, f))}
+}
+{{if .NumArgs}}
+// With restricts the declaration to calls with the given arguments
+func (d Decl{{.Suffix}}{{.DeclTypeArgs}}) With({{.Params}}) Decl{{.Suffix}}{{.DeclTypeArgs}} {
+	d.decl.args = []interface{}{ {{- .ParamNames}}}
+	return d
+}
+{{end}}{{if .NumRes}}
+// Returns specifies the output parameters of the declared call
+func (d Decl{{.Suffix}}{{.DeclTypeArgs}}) Returns({{.ResParams}}) {
+	d.decl.Return({{.ResParamNames}})
+}
+{{end}}{{end}}`
+
+var typedTmpl = template.Must(template.New("typed").Parse(typedTmplStr))
+
+type arity struct {
+	NumArgs, NumRes int
+}
+
+func (a arity) Suffix() string {
+	return fmt.Sprintf("%dx%d", a.NumArgs, a.NumRes)
+}
+
+func (a arity) names(prefix string, n int) []string {
+	var names []string
+	for i := 1; i <= n; i++ {
+		names = append(names, fmt.Sprintf("%s%d", prefix, i))
+	}
+	return names
+}
+
+func (a arity) typeNames() []string {
+	return append(a.names("A", a.NumArgs), a.names("R", a.NumRes)...)
+}
+
+func (a arity) TypeParams() string {
+	names := a.typeNames()
+	if len(names) == 0 {
+		return ""
+	}
+	return ", " + strings.Join(names, ", ")
+}
+
+func (a arity) DeclTypeParams() string {
+	names := a.typeNames()
+	if len(names) == 0 {
+		return ""
+	}
+	return "[" + strings.Join(names, ", ") + " any]"
+}
+
+func (a arity) DeclTypeArgs() string {
+	names := a.typeNames()
+	if len(names) == 0 {
+		return ""
+	}
+	return "[" + strings.Join(names, ", ") + "]"
+}
+
+func (a arity) Args() string {
+	var s string
+	for _, name := range a.names("A", a.NumArgs) {
+		s += ", " + name
+	}
+	return s
+}
+
+func (a arity) Results() string {
+	names := a.names("R", a.NumRes)
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		return " " + names[0]
+	}
+	return " (" + strings.Join(names, ", ") + ")"
+}
+
+func (a arity) params(prefix string, n int) string {
+	var params []string
+	for i := 1; i <= n; i++ {
+		params = append(params, fmt.Sprintf("%s%d %s%d", strings.ToLower(prefix), i, prefix, i))
+	}
+	return strings.Join(params, ", ")
+}
+
+func (a arity) Params() string {
+	return a.params("A", a.NumArgs)
+}
+
+func (a arity) ParamNames() string {
+	return strings.ToLower(strings.Join(a.names("A", a.NumArgs), ", "))
+}
+
+func (a arity) ResParams() string {
+	return a.params("R", a.NumRes)
+}
+
+func (a arity) ResParamNames() string {
+	return strings.ToLower(strings.Join(a.names("R", a.NumRes), ", "))
+}
+
+func main() {
+	var arities []arity
+	for args := 0; args <= maxArgs; args++ {
+		for res := 0; res <= maxResults; res++ {
+			arities = append(arities, arity{args, res})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := typedTmpl.Execute(&buf, arities); err != nil {
+		panic(err)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		panic(err)
+	}
+
+	if err := os.WriteFile("typed_gen.go", src, 0644); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,90 @@
+package mock
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Matcher matches a call argument. Matchers can be passed to OnCall/ExpectCall
+// instead of argument values
+type Matcher interface {
+	Matches(arg interface{}) bool
+	String() string
+}
+
+// Arg is a typed argument matcher. Generated mock recorders accept Arg of the method parameter type.
+// The zero Arg matches any argument
+type Arg[T any] struct {
+	desc  string
+	match func(arg T) bool
+}
+
+// Eq matches an argument which is equal to 'v'
+func Eq[T any](v T) Arg[T] {
+	return Arg[T]{
+		desc: fmt.Sprint(v),
+		match: func(arg T) bool {
+			return reflect.DeepEqual(arg, v)
+		},
+	}
+}
+
+// Any matches any argument
+func Any[T any]() Arg[T] {
+	return Arg[T]{
+		desc: "any",
+		match: func(T) bool {
+			return true
+		},
+	}
+}
+
+// Cond matches an argument satisfying the 'match' condition. 'desc' describes the condition in failures
+func Cond[T any](desc string, match func(arg T) bool) Arg[T] {
+	return Arg[T]{desc: desc, match: match}
+}
+
+// Matches reports whether 'arg' is matched
+func (a Arg[T]) Matches(arg interface{}) bool {
+	if a.match == nil {
+		_, ok := arg.(T)
+		return ok || arg == nil
+	}
+
+	if arg == nil {
+		var zero T
+		return a.match(zero)
+	}
+
+	v, ok := arg.(T)
+	return ok && a.match(v)
+}
+
+func (a Arg[T]) String() string {
+	if a.match == nil {
+		return "any"
+	}
+	return a.desc
+}
+
+func (a Arg[T]) argType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// typedMatcher is a Matcher of arguments of a single type, e.g. Arg
+type typedMatcher interface {
+	Matcher
+	argType() reflect.Type
+}
+
+// validateMatcher checks that 'm' can match arguments of the parameter type 'paramType'
+func validateMatcher(paramType reflect.Type, m typedMatcher) error {
+	argType := m.argType()
+	if argType == paramType ||
+		argType.Kind() == reflect.Interface && paramType.Implements(argType) ||
+		paramType.Kind() == reflect.Interface && argType.Implements(paramType) {
+		return nil
+	}
+
+	return fmt.Errorf("matcher %s of type %s never matches type %s", m, argType, paramType)
+}
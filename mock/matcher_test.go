@@ -0,0 +1,70 @@
+package mock
+
+import (
+	"testing"
+)
+
+func TestOnCallWithMatchers(t *testing.T) {
+	obj := &myObj{New(t)}
+
+	OnCall(obj, myInterface.doSmth, Eq(1)).Return(&myType{"one"}, nil)
+	OnCall(obj, myInterface.doSmth, Cond("even", func(arg int) bool { return arg%2 == 0 })).Return(&myType{"even"}, nil)
+	OnCall(obj, myInterface.doSmth, Any[int]()).Return(&myType{"any"}, nil)
+
+	for arg, data := range map[int]string{1: "one", 2: "even", 3: "any"} {
+		if v, _ := obj.doSmth(arg); v.data != data {
+			t.Fatalf(`doSmth(%d): expected "%s", got "%s"`, arg, data, v.data)
+		}
+	}
+}
+
+func TestExpectCallWithMatcherMismatch(t *testing.T) {
+	tm := new(tmock)
+	m := New(tm)
+	obj := &myObj{m}
+
+	ExpectCall(obj, myInterface.doSmth, Eq(1))
+
+	obj.doSmth(2)
+
+	if !tm.fail {
+		t.Fatal("!tm.fail")
+	}
+}
+
+func TestNilArgMatchers(t *testing.T) {
+	obj := &myObj{New(t)}
+
+	ExpectCall(obj, myInterface.slice, Eq[[]int](nil)).Return([]int{1})
+
+	if out := obj.slice(nil); len(out) != 1 {
+		t.Fatal("len(out) != 1")
+	}
+}
+
+func TestMatcherOfInvalidType(t *testing.T) {
+	obj := &myObj{New(t)}
+
+	defer expectPanic(t)
+	OnCall(obj, myInterface.doSmth, Eq("1"))
+}
+
+func TestInterfaceMatchers(t *testing.T) {
+	obj := &myObj{New(t)}
+
+	OnCall(obj, myInterface.doSmth, Any[any]()).Return(&myType{"any"}, nil)
+
+	if v, _ := obj.doSmth(1); v.data != "any" {
+		t.Fatalf(`doSmth(1): expected "any", got "%s"`, v.data)
+	}
+}
+
+func TestZeroArgMatchesAny(t *testing.T) {
+	obj := &myObj{New(t)}
+
+	ExpectCall(obj, myInterface.doSmth, Arg[int]{}).Return(&myType{"any"}, nil)
+
+	if v, _ := obj.doSmth(3); v.data != "any" {
+		t.Fatalf(`doSmth(3): expected "any", got "%s"`, v.data)
+	}
+}
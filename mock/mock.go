@@ -9,6 +9,7 @@ import (
 
 // OnCall declares that 'obj' method 'f' can be called with 'args' during the test.
 // If 'args' are not specified 'f' can be called with any input parameters.
+// For a mock of a function type 'f' is a value of the type, e.g. Clock(nil).
 // The mocked function will return default constructed values in case of output parameters
 // are not specified via Returner.Return method
 func OnCall(obj interface{}, f interface{}, args ...interface{}) Returner {
@@ -45,8 +46,35 @@ type M interface {
 }
 
 // New creates mock.M for the given *testing.T
-func New(t TestingT) M {
-	return &core{t: t}
+func New(t TestingT, opts ...Option) M {
+	c := &core{t: t}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.checkOnCleanup {
+		ct, ok := t.(cleanupT)
+		if !ok {
+			t.Fatalf("mock: CheckOnCleanup requires %T to have a Cleanup method", t)
+			return c
+		}
+		ct.Cleanup(c.CheckExpectations)
+	}
+	return c
+}
+
+// Option configures mock.M created by New
+type Option func(*core)
+
+// CheckOnCleanup makes mock.M call CheckExpectations when the test finishes.
+// It requires TestingT to have a Cleanup method like *testing.T has
+func CheckOnCleanup() Option {
+	return func(c *core) { c.checkOnCleanup = true }
+}
+
+// NoCheckOnCleanup cancels CheckOnCleanup, e.g. if CheckExpectations is called explicitly
+func NoCheckOnCleanup() Option {
+	return func(c *core) { c.checkOnCleanup = false }
 }
 
 // TestingT is a ligth interface of testing.T. Is required for mock package been testable
@@ -54,6 +82,10 @@ type TestingT interface {
 	Fatalf(format string, args ...interface{})
 }
 
+type cleanupT interface {
+	Cleanup(func())
+}
+
 func getCore(obj interface{}) *core {
 	objVal := reflect.ValueOf(obj)
 	if objVal.Kind() != reflect.Ptr && objVal.Kind() != reflect.Interface {
@@ -66,6 +98,9 @@ type core struct {
 	t        TestingT
 	calls    []*callDeclaration
 	expCalls []*expectedCallDeclaration
+	fixtures []string // names of fixtures being applied
+
+	checkOnCleanup bool
 }
 
 type callDeclaration struct {
@@ -73,10 +108,16 @@ type callDeclaration struct {
 	fID  funcIdentity
 	args []interface{}
 	out  []interface{}
+
+	fixture string // name of the fixture made the declaration
 }
 
 func (cd callDeclaration) String() string {
-	return callToStr(cd.obj, cd.fID.name, cd.args)
+	str := callToStr(cd.obj, cd.fID.String(), cd.args)
+	if cd.fixture != "" {
+		str += fmt.Sprintf(" (from fixture %s)", cd.fixture)
+	}
+	return str
 }
 
 type expectedCallDeclaration struct {
@@ -86,17 +127,36 @@ type expectedCallDeclaration struct {
 
 func (cd *callDeclaration) satisfied(obj interface{}, fID funcIdentity, args []interface{}) bool {
 	return cd.obj == obj &&
-		reflect.DeepEqual(cd.fID, fID) &&
-		(cd.args == nil || reflect.DeepEqual(cd.args, args))
+		cd.fID == fID &&
+		(cd.args == nil || argsMatch(cd.args, args))
+}
+
+func argsMatch(declared []interface{}, args []interface{}) bool {
+	if len(declared) != len(args) {
+		return false
+	}
+
+	for i, arg := range args {
+		if m, ok := declared[i].(Matcher); ok {
+			if !m.Matches(arg) {
+				return false
+			}
+			continue
+		}
+
+		if !reflect.DeepEqual(declared[i], arg) {
+			return false
+		}
+	}
+
+	return true
 }
 
 type call struct {
 	decl *callDeclaration
 }
 
-func adaptArgs(args []interface{}, f interface{}) {
-	fType := reflect.TypeOf(f)
-
+func adaptArgs(args []interface{}, fType reflect.Type) {
 	for i, arg := range args {
 		argType := reflect.TypeOf(arg)
 		fArgType := fType.In(i + 1)
@@ -104,6 +164,10 @@ func adaptArgs(args []interface{}, f interface{}) {
 			continue
 		}
 
+		if _, ok := arg.(Matcher); ok {
+			continue
+		}
+
 		if arg == nil {
 			args[i] = reflect.Zero(fArgType).Interface()
 			continue
@@ -116,29 +180,31 @@ func adaptArgs(args []interface{}, f interface{}) {
 }
 
 func (c *core) onCall(obj interface{}, f interface{}, args ...interface{}) Returner {
-	validateCall(obj, f, args, true)
+	fID := validateCall(obj, f, args, true)
 	if args != nil {
-		adaptArgs(args, f)
+		adaptArgs(args, fID.fType)
 	}
 	cd := &callDeclaration{
-		obj:  obj,
-		fID:  getFuncID(f),
-		args: args,
+		obj:     obj,
+		fID:     fID,
+		args:    args,
+		fixture: c.fixtureName(),
 	}
 	c.calls = append(c.calls, cd)
 	return cd
 }
 
 func (c *core) expectCall(obj interface{}, f interface{}, args ...interface{}) Returner {
-	validateCall(obj, f, args, true)
+	fID := validateCall(obj, f, args, true)
 	if args != nil {
-		adaptArgs(args, f)
+		adaptArgs(args, fID.fType)
 	}
 	ecd := &expectedCallDeclaration{
 		callDeclaration: callDeclaration{
-			obj:  obj,
-			fID:  getFuncID(f),
-			args: args,
+			obj:     obj,
+			fID:     fID,
+			args:    args,
+			fixture: c.fixtureName(),
 		},
 		used: false,
 	}
@@ -147,9 +213,8 @@ func (c *core) expectCall(obj interface{}, f interface{}, args ...interface{}) R
 }
 
 func (c *core) call(obj interface{}, f interface{}, args ...interface{}) Returner {
-	validateCall(obj, f, args, false)
+	fID := validateCall(obj, f, args, false)
 
-	fID := getFuncID(f)
 	for i, exp := range c.expCalls {
 		if exp.used || !exp.satisfied(obj, fID, args) {
 			continue
@@ -158,7 +223,7 @@ func (c *core) call(obj interface{}, f interface{}, args ...interface{}) Returne
 		if i != 0 && !c.expCalls[i-1].used {
 			for _, exp := range c.expCalls {
 				if !exp.used {
-					c.t.Fatalf(`%s must be called before %s`, exp, callToStr(obj, fName(f), args))
+					c.t.Fatalf(`%s must be called before %s`, exp, callToStr(obj, fID.String(), args))
 					return &call{nil}
 				}
 			}
@@ -174,7 +239,7 @@ func (c *core) call(obj interface{}, f interface{}, args ...interface{}) Returne
 		}
 	}
 
-	c.t.Fatalf(`%s called but not defined`, callToStr(obj, fName(f), args))
+	c.t.Fatalf(`%s called but not defined`, callToStr(obj, fID.String(), args))
 	return &call{nil}
 }
 
@@ -190,12 +255,12 @@ func (c *core) CheckExpectations() {
 func (cd *callDeclaration) Return(out ...interface{}) {
 	if len(out) != cd.fID.fType.NumOut() {
 		panic(fmt.Sprintf(`Invalid %s return values: count must be %d`,
-			cd.fID.name, cd.fID.fType.NumOut()))
+			cd.fID, cd.fID.fType.NumOut()))
 	}
 
 	for i, gotOut := range out {
 		if err := validateFuncParam(cd.fID.fType.Out(i), gotOut); err != nil {
-			panic(fmt.Sprintf(`Invalid %s %d-th return value: %s`, cd.fID.name, i+1, err.Error()))
+			panic(fmt.Sprintf(`Invalid %s %d-th return value: %s`, cd.fID, i+1, err.Error()))
 		}
 	}
 
@@ -249,53 +314,229 @@ func (c *call) Return(out ...interface{}) {
 	}
 }
 
-func validateCall(obj interface{}, f interface{}, args []interface{}, optionalArgs bool) {
+// validateCall checks the call of 'obj' method 'f' and returns the method identity
+func validateCall(obj interface{}, f interface{}, args []interface{}, optionalArgs bool) funcIdentity {
 	objVal := reflect.ValueOf(obj)
 
 	fType := reflect.TypeOf(f)
-	if fType.Kind() != reflect.Func {
+	if fType == nil || fType.Kind() != reflect.Func {
 		panic("f must be kind of function")
 	}
 
-	if fType.NumIn() < 1 || !objVal.Type().AssignableTo(fType.In(0)) {
-		panic("f must be an obj interface method")
+	fID, err := getFuncID(f)
+	if err != nil {
+		panic(err.Error())
+	}
+	fType = fID.fType // arguments follow the receiver of a method expression
+
+	if fID.iface.Kind() == reflect.Func {
+		if !funcMockOf(objVal.Type(), fID.iface) {
+			panic(fmt.Sprintf("obj must be a mock of %s: %s has no method Fn() %s", fID, objVal.Type(), fID.iface))
+		}
+	} else if !objVal.Type().AssignableTo(fID.iface) {
+		panic(fmt.Sprintf("f must be an obj interface method: %s does not implement %s", objVal.Type(), fID.iface))
 	}
 
 	if optionalArgs && args == nil {
-		return
+		return fID
 	}
 
 	if fType.NumIn()-1 != len(args) {
 		panic(fmt.Sprintf(`Invalid %s args count. Got %d, expected %d`,
-			fName(f), len(args), fType.NumIn()-1))
+			fID, len(args), fType.NumIn()-1))
 	}
 
 	for i, arg := range args {
 		paramType := fType.In(i + 1)
+		if m, ok := arg.(typedMatcher); ok && optionalArgs {
+			if err := validateMatcher(paramType, m); err != nil {
+				panic(fmt.Sprintf(`Invalid %s %d-th arg: %s`, fID, i+1, err.Error()))
+			}
+			continue
+		}
+		if _, ok := arg.(Matcher); ok && optionalArgs {
+			continue
+		}
+
 		if err := validateFuncParam(paramType, arg); err != nil {
-			panic(fmt.Sprintf(`Invalid %s %d-th arg: %s`, fName(f), i+1, err.Error()))
+			panic(fmt.Sprintf(`Invalid %s %d-th arg: %s`, fID, i+1, err.Error()))
 		}
 	}
+
+	return fID
 }
 
+// funcIdentity identifies an interface method: the interface type and the method name.
+// Method expressions of different instantiations of a generic interface
+// may share the code and thus the function name, so the interface type
+// (the first parameter of the method expression) is the part of the identity.
+// Same-named methods of different interfaces are different methods.
+// Calls of a function type mock are identified by the function type alone.
 type funcIdentity struct {
-	name  string
-	fType reflect.Type
+	iface reflect.Type // interface type, possibly unnamed, or named function type
+	name  string       // method name; empty for a function type
+	fType reflect.Type // method expression type; for a function type, its signature with the type prepended to the parameters
+}
+
+func (id funcIdentity) String() string {
+	iface := id.iface.Name()
+	if iface == "" {
+		// An interface literal, e.g. of a struct field.
+		iface = "(" + id.iface.String() + ")"
+	}
+	if id.name == "" {
+		return iface
+	}
+	return iface + "." + id.name
+}
+
+// getFuncID returns the identity of the method expression f, e.g. Storage.GetValue,
+// or of the named function type of f, e.g. Clock(nil).
+// It fails if f is neither a method expression of an interface nor a value of a named function type.
+func getFuncID(f interface{}) (funcIdentity, error) {
+	fType := reflect.TypeOf(f)
+	if fType.Name() != "" {
+		return funcTypeID(fType), nil
+	}
+	name := fName(f)
+
+	if fType.NumIn() < 1 || fType.In(0).Kind() != reflect.Interface {
+		return funcIdentity{}, fmt.Errorf(
+			"f must be an interface method expression, e.g. Storage.GetValue: %s is %s", name, fType)
+	}
+
+	iface := fType.In(0)
+	m, ok := iface.MethodByName(name)
+	if !ok || !methodExprOf(m.Type, fType) || !isMethodExpr(f, iface, name) {
+		return funcIdentity{}, fmt.Errorf(
+			"f must be an interface method expression, e.g. Storage.GetValue: %s is not a method of %s", name, iface)
+	}
+
+	return funcIdentity{iface: iface, name: name, fType: fType}, nil
 }
 
-func getFuncID(f interface{}) funcIdentity {
-	return funcIdentity{
-		name:  fName(f),
-		fType: reflect.TypeOf(f),
+// isMethodExpr reports whether f is the method expression of the method name of iface
+// rather than a function having the same signature, e.g. "func doSmth(myInterface, int)".
+// Its function name is "<iface pkg path>.<iface name>.<method>", possibly with type arguments
+// and a "·<n>" suffix of the iface name if iface is declared in a function,
+// or "go:<iface type>.<method>" for an interface literal.
+func isMethodExpr(f interface{}, iface reflect.Type, name string) bool {
+	want := "go:" + iface.String()
+	if iface.Name() != "" {
+		want = symbolPrefix(iface.PkgPath()) + "." + iface.Name()
+	}
+	want = stripTypeArgs(want + "." + name)
+
+	got := runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name()
+	got = strings.TrimSuffix(stripTypeArgs(got), "-fm")
+	if typ, ok := strings.CutSuffix(got, "."+name); ok {
+		got = stripLocalSuffix(typ) + "." + name
 	}
+	return got == want
 }
 
+// stripLocalSuffix removes the "·<n>" suffix numbering the types declared in functions
+// from the type symbol name typ, e.g. "pkg.Local·1" becomes "pkg.Local".
+func stripLocalSuffix(typ string) string {
+	i := strings.LastIndex(typ, "·")
+	if i < 0 {
+		return typ
+	}
+	n := typ[i+len("·"):]
+	if n == "" || strings.Trim(n, "0123456789") != "" {
+		return typ
+	}
+	return typ[:i]
+}
+
+// symbolPrefix returns the package path pkgPath as it prefixes the symbol names of the package:
+// dots of the last path element and special characters are escaped, e.g. "gopkg.in/yaml%2ev3".
+func symbolPrefix(pkgPath string) string {
+	last := strings.LastIndex(pkgPath, "/")
+	var b strings.Builder
+	for i := 0; i < len(pkgPath); i++ {
+		c := pkgPath[i]
+		if c <= ' ' || c >= 0x7f || c == '%' || c == '"' || c == '.' && i > last {
+			fmt.Fprintf(&b, "%%%02x", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// funcTypeID returns the identity of calls of the function type fType.
+// Its signature gets the function type as the first parameter, so it is indexed like a method expression.
+func funcTypeID(fType reflect.Type) funcIdentity {
+	in := []reflect.Type{fType}
+	for i := 0; i < fType.NumIn(); i++ {
+		in = append(in, fType.In(i))
+	}
+	out := make([]reflect.Type, fType.NumOut())
+	for i := range out {
+		out[i] = fType.Out(i)
+	}
+	return funcIdentity{iface: fType, fType: reflect.FuncOf(in, out, fType.IsVariadic())}
+}
+
+// funcMockOf reports whether objType is a mock of the function type fType,
+// which has the method Fn returning the mocked function.
+func funcMockOf(objType reflect.Type, fType reflect.Type) bool {
+	m, ok := objType.MethodByName("Fn")
+	return ok && m.Type.NumIn() == 1 && m.Type.NumOut() == 1 && m.Type.Out(0) == fType
+}
+
+// methodExprOf reports whether fType is the type of a method expression
+// of the interface method having type mType.
+func methodExprOf(mType reflect.Type, fType reflect.Type) bool {
+	if mType.NumIn()+1 != fType.NumIn() || mType.NumOut() != fType.NumOut() ||
+		mType.IsVariadic() != fType.IsVariadic() {
+		return false
+	}
+
+	for i := 0; i < mType.NumIn(); i++ {
+		if mType.In(i) != fType.In(i+1) {
+			return false
+		}
+	}
+
+	for i := 0; i < mType.NumOut(); i++ {
+		if mType.Out(i) != fType.Out(i) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// fName returns the method name of the method expression f.
+// Function names of generic interfaces method expressions contain type arguments
+// which can also contain dots, e.g. "pkg.Repo[...].Get" or "pkg.Repo[go.shape.string].Get",
+// so they are stripped before taking the last name token.
 func fName(f interface{}) string {
 	fullName := runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name()
+	fullName = strings.TrimSuffix(stripTypeArgs(fullName), "-fm")
 	tokens := strings.Split(fullName, ".")
 	return tokens[len(tokens)-1]
 }
 
+// stripTypeArgs removes bracketed type arguments from the function name.
+func stripTypeArgs(name string) string {
+	var b strings.Builder
+	depth := 0
+	for _, r := range name {
+		switch {
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+		case depth == 0:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
 func callToStr(obj interface{}, name string, args []interface{}) string {
 	if args == nil {
 		return name
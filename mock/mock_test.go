@@ -50,10 +50,12 @@ func (obj *myObj) slice(in []int) (out []int) {
 
 type tmock struct {
 	fail bool
+	msgs []string
 }
 
 func (t *tmock) Fatalf(format string, args ...interface{}) {
 	t.fail = true
+	t.msgs = append(t.msgs, fmt.Sprintf(format, args...))
 }
 
 func TestOnCallBase(t *testing.T) {
@@ -296,3 +298,320 @@ func expectPanic(t *testing.T) {
 		t.Fatal("panic expected")
 	}
 }
+
+type valuer[T any] interface {
+	value(key string) T
+}
+
+type valuerObj[T any] struct {
+	M
+}
+
+func (obj *valuerObj[T]) value(key string) (v T) {
+	Call(obj, valuer[T].value, key).Return(&v)
+	return
+}
+
+func onValue[T any](obj interface{}, key string, v T) {
+	OnCall(obj, valuer[T].value, key).Return(v)
+}
+
+func TestGenericInterfaceMethod(t *testing.T) {
+	intObj := &valuerObj[int]{New(t)}
+	strObj := &valuerObj[string]{New(t)}
+
+	OnCall(intObj, valuer[int].value, "key").Return(1)
+	onValue(strObj, "key", "1")
+
+	if v := intObj.value("key"); v != 1 {
+		t.Fatalf("v != 1: %d", v)
+	}
+
+	if v := strObj.value("key"); v != "1" {
+		t.Fatalf(`v != "1": %s`, v)
+	}
+}
+
+func TestGenericInterfaceInstantiationsDiffer(t *testing.T) {
+	intID, _ := getFuncID(valuer[int].value)
+	strID, _ := getFuncID(valuer[string].value)
+	if intID == strID {
+		t.Fatal("valuer[int].value and valuer[string].value identities are equal")
+	}
+
+	if name := fName(valuer[int].value); name != "value" {
+		t.Fatalf(`fName(valuer[int].value) != "value": %s`, name)
+	}
+}
+
+// doSmth2 has the signature of the method expression myInterface.doSmth2.
+func doSmth2(myInterface) myType {
+	return myType{}
+}
+
+func TestFuncWithMethodExprSignature(t *testing.T) {
+	if _, err := getFuncID(doSmth2); err == nil {
+		t.Fatal("doSmth2 is accepted as myInterface.doSmth2")
+	}
+
+	obj := &myObj{New(t)}
+	defer expectPanic(t)
+	OnCall(obj, doSmth2)
+}
+
+func TestSymbolPrefix(t *testing.T) {
+	cases := map[string]string{
+		"main":                          "main",
+		"github.com/unkeep/gomock/mock": "github.com/unkeep/gomock/mock",
+		"gopkg.in/yaml.v3":              "gopkg.in/yaml%2ev3",
+	}
+	for path, want := range cases {
+		if got := symbolPrefix(path); got != want {
+			t.Fatalf("symbolPrefix(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestStripTypeArgs(t *testing.T) {
+	cases := map[string]string{
+		"pkg.Repo[...].Get":                 "pkg.Repo.Get",
+		"pkg.Repo[go.shape.string].Get":     "pkg.Repo.Get",
+		"pkg.Repo[map[string]pkg.User].Get": "pkg.Repo.Get",
+		"pkg.Repo.Get[...]":                 "pkg.Repo.Get",
+	}
+
+	for in, out := range cases {
+		if got := stripTypeArgs(in); got != out {
+			t.Fatalf(`stripTypeArgs(%s): expected "%s", got "%s"`, in, out, got)
+		}
+	}
+}
+
+func TestLocalInterfaceMethod(t *testing.T) {
+	type getter interface {
+		value(key string) int
+	}
+	type genericGetter[T any] interface {
+		value(key string) T
+	}
+
+	intObj := &valuerObj[int]{New(t)}
+	strObj := &valuerObj[string]{New(t)}
+
+	OnCall(intObj, getter.value, "key").Return(1)
+	OnCall(strObj, genericGetter[string].value, "key").Return("1")
+
+	var v int
+	Call(intObj, getter.value, "key").Return(&v)
+	if v != 1 {
+		t.Fatalf("v != 1: %d", v)
+	}
+
+	var s string
+	Call(strObj, genericGetter[string].value, "key").Return(&s)
+	if s != "1" {
+		t.Fatalf(`s != "1": %s`, s)
+	}
+}
+
+func TestStripLocalSuffix(t *testing.T) {
+	cases := map[string]string{
+		"pkg.Local·1":  "pkg.Local",
+		"pkg.Local·12": "pkg.Local",
+		"pkg.Local":    "pkg.Local",
+		"pkg.Local·":   "pkg.Local·",
+		"pkg.Local·x":  "pkg.Local·x",
+	}
+	for in, out := range cases {
+		if got := stripLocalSuffix(in); got != out {
+			t.Fatalf(`stripLocalSuffix(%s): expected "%s", got "%s"`, in, out, got)
+		}
+	}
+}
+
+type reader interface {
+	close() error
+}
+
+type writer interface {
+	close() error
+}
+
+type readWriterObj struct {
+	M
+}
+
+func (obj *readWriterObj) close() (err error) {
+	Call(obj, writer.close).Return(&err)
+	return
+}
+
+func TestSameNamedMethodsOfDifferentInterfaces(t *testing.T) {
+	tm := new(tmock)
+	obj := &readWriterObj{New(tm)}
+
+	OnCall(obj, reader.close)
+
+	obj.close()
+
+	if !tm.fail {
+		t.Fatal("!tm.fail")
+	}
+}
+
+func TestFuncLiteralDeclaration(t *testing.T) {
+	obj := &myObj{New(t)}
+	defer expectPanic(t)
+	OnCall(obj, func(myInterface) myType { return myType{} })
+}
+
+func TestConcreteMethodExpressionDeclaration(t *testing.T) {
+	obj := &myObj{New(t)}
+	defer expectPanic(t)
+	OnCall(obj, (*myObj).doSmth2)
+}
+
+type cleanupMock struct {
+	tmock
+	cleanups []func()
+}
+
+func (t *cleanupMock) Cleanup(f func()) {
+	t.cleanups = append(t.cleanups, f)
+}
+
+func TestCheckOnCleanup(t *testing.T) {
+	tm := &cleanupMock{}
+	obj := &myObj{New(tm, CheckOnCleanup())}
+	ExpectCall(obj, myInterface.doSmth2)
+
+	if len(tm.cleanups) != 1 {
+		t.Fatalf("%d cleanups registered, want 1", len(tm.cleanups))
+	}
+	tm.cleanups[0]()
+	if !tm.fail {
+		t.Fatal("unsatisfied expectation wasn't reported on cleanup")
+	}
+}
+
+func TestNoCheckOnCleanup(t *testing.T) {
+	tm := &cleanupMock{}
+	New(tm, CheckOnCleanup(), NoCheckOnCleanup())
+
+	if len(tm.cleanups) != 0 {
+		t.Fatalf("%d cleanups registered, want 0", len(tm.cleanups))
+	}
+}
+
+func TestCheckOnCleanupWithoutCleanup(t *testing.T) {
+	tm := &tmock{}
+	New(tm, CheckOnCleanup())
+
+	if !tm.fail {
+		t.Fatal("missing Cleanup method wasn't reported")
+	}
+}
+
+type clock func(zone string) (int64, error)
+
+type clockObj struct {
+	M
+}
+
+func (obj *clockObj) Fn() clock {
+	return func(zone string) (t int64, err error) {
+		Call(obj, clock(nil), zone).Return(&t, &err)
+		return
+	}
+}
+
+type handler[T any] func(req T, opts ...string) T
+
+type handlerObj[T any] struct {
+	M
+}
+
+func (obj *handlerObj[T]) Fn() handler[T] {
+	return func(req T, opts ...string) (resp T) {
+		Call(obj, handler[T](nil), req, opts).Return(&resp)
+		return
+	}
+}
+
+func TestFuncTypeMock(t *testing.T) {
+	obj := &clockObj{New(t)}
+	ExpectCall(obj, clock(nil), "UTC").Return(int64(1), nil)
+	OnCall(obj, clock(nil)).Return(int64(2), fmt.Errorf("unknown zone"))
+
+	now := obj.Fn()
+	if v, err := now("UTC"); v != 1 || err != nil {
+		t.Fatalf("got %d, %v, want 1, nil", v, err)
+	}
+	if v, err := now("Mars"); v != 2 || err == nil {
+		t.Fatalf("got %d, %v, want 2, unknown zone", v, err)
+	}
+	obj.CheckExpectations()
+}
+
+func TestGenericVariadicFuncTypeMock(t *testing.T) {
+	obj := &handlerObj[string]{New(t)}
+	OnCall(obj, handler[string](nil), "req", []string{"a"}).Return("resp")
+
+	if resp := obj.Fn()("req", "a"); resp != "resp" {
+		t.Fatalf(`got %q, want "resp"`, resp)
+	}
+}
+
+func TestFuncTypeMockExpectation(t *testing.T) {
+	tm := new(tmock)
+	obj := &clockObj{New(tm)}
+	ExpectCall(obj, clock(nil), "UTC")
+
+	obj.Fn()("Mars")
+	if !tm.fail {
+		t.Fatal("unexpected call wasn't reported")
+	}
+}
+
+func TestFuncTypeOfOtherObj(t *testing.T) {
+	obj := &myObj{New(t)}
+	defer expectPanic(t)
+	OnCall(obj, clock(nil))
+}
+
+func TestInvalidFuncTypeArgs(t *testing.T) {
+	obj := &clockObj{New(t)}
+	defer expectPanic(t)
+	OnCall(obj, clock(nil), myType{})
+}
+
+type getter = interface{ Get(key string) string }
+
+type inlineObj struct {
+	M
+}
+
+func (o *inlineObj) Get(key string) (val string) {
+	Call(o, (interface{ Get(string) string }).Get, key).Return(&val)
+	return
+}
+
+func TestInlineInterfaceMock(t *testing.T) {
+	obj := &inlineObj{New(t)}
+	OnCall(obj, (interface{ Get(string) string }).Get, "key").Return("val")
+
+	var g getter = obj
+	if val := g.Get("key"); val != "val" {
+		t.Fatalf(`got %q, want "val"`, val)
+	}
+}
+
+func TestInlineInterfaceName(t *testing.T) {
+	fID, err := getFuncID((interface{ Get(string) string }).Get)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fID.String(), "(interface { Get(string) string }).Get"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
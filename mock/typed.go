@@ -0,0 +1,19 @@
+package mock
+
+//go:generate go run gen_typed.go
+
+// The typed declaration API is a compile-time checked alternative to OnCall/ExpectCall.
+// The functions are named by the number of method arguments and results, e.g. for
+//  GetValue(key string) (int, error)
+// the declaration looks like
+//  mock.On1x2(st, Storage.GetValue).With("key").Returns(123, nil)
+// Wrong argument or result types are compile errors. Methods having up to 5 arguments
+// and up to 3 results are supported. Variadic methods should be declared via OnCall/ExpectCall.
+
+// declaration returns the call declaration behind a Returner made by OnCall/ExpectCall
+func declaration(r Returner) *callDeclaration {
+	if ecd, ok := r.(*expectedCallDeclaration); ok {
+		return &ecd.callDeclaration
+	}
+	return r.(*callDeclaration)
+}
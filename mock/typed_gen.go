@@ -0,0 +1,573 @@
+// Code generated by gen_typed.go. DO NOT EDIT.
+
+package mock
+
+// Decl0x0 is a typed declaration of a method with 0 argument(s) and 0 result(s)
+type Decl0x0 struct {
+	decl *callDeclaration
+}
+
+// On0x0 is a typed version of OnCall for a method with 0 argument(s) and 0 result(s)
+func On0x0[I any](obj interface{}, f func(I)) Decl0x0 {
+	return Decl0x0{declaration(OnCall(obj, f))}
+}
+
+// Expect0x0 is a typed version of ExpectCall for a method with 0 argument(s) and 0 result(s)
+func Expect0x0[I any](obj interface{}, f func(I)) Decl0x0 {
+	return Decl0x0{declaration(ExpectCall(obj, f))}
+}
+
+// Decl0x1 is a typed declaration of a method with 0 argument(s) and 1 result(s)
+type Decl0x1[R1 any] struct {
+	decl *callDeclaration
+}
+
+// On0x1 is a typed version of OnCall for a method with 0 argument(s) and 1 result(s)
+func On0x1[I, R1 any](obj interface{}, f func(I) R1) Decl0x1[R1] {
+	return Decl0x1[R1]{declaration(OnCall(obj, f))}
+}
+
+// Expect0x1 is a typed version of ExpectCall for a method with 0 argument(s) and 1 result(s)
+func Expect0x1[I, R1 any](obj interface{}, f func(I) R1) Decl0x1[R1] {
+	return Decl0x1[R1]{declaration(ExpectCall(obj, f))}
+}
+
+// Returns specifies the output parameters of the declared call
+func (d Decl0x1[R1]) Returns(r1 R1) {
+	d.decl.Return(r1)
+}
+
+// Decl0x2 is a typed declaration of a method with 0 argument(s) and 2 result(s)
+type Decl0x2[R1, R2 any] struct {
+	decl *callDeclaration
+}
+
+// On0x2 is a typed version of OnCall for a method with 0 argument(s) and 2 result(s)
+func On0x2[I, R1, R2 any](obj interface{}, f func(I) (R1, R2)) Decl0x2[R1, R2] {
+	return Decl0x2[R1, R2]{declaration(OnCall(obj, f))}
+}
+
+// Expect0x2 is a typed version of ExpectCall for a method with 0 argument(s) and 2 result(s)
+func Expect0x2[I, R1, R2 any](obj interface{}, f func(I) (R1, R2)) Decl0x2[R1, R2] {
+	return Decl0x2[R1, R2]{declaration(ExpectCall(obj, f))}
+}
+
+// Returns specifies the output parameters of the declared call
+func (d Decl0x2[R1, R2]) Returns(r1 R1, r2 R2) {
+	d.decl.Return(r1, r2)
+}
+
+// Decl0x3 is a typed declaration of a method with 0 argument(s) and 3 result(s)
+type Decl0x3[R1, R2, R3 any] struct {
+	decl *callDeclaration
+}
+
+// On0x3 is a typed version of OnCall for a method with 0 argument(s) and 3 result(s)
+func On0x3[I, R1, R2, R3 any](obj interface{}, f func(I) (R1, R2, R3)) Decl0x3[R1, R2, R3] {
+	return Decl0x3[R1, R2, R3]{declaration(OnCall(obj, f))}
+}
+
+// Expect0x3 is a typed version of ExpectCall for a method with 0 argument(s) and 3 result(s)
+func Expect0x3[I, R1, R2, R3 any](obj interface{}, f func(I) (R1, R2, R3)) Decl0x3[R1, R2, R3] {
+	return Decl0x3[R1, R2, R3]{declaration(ExpectCall(obj, f))}
+}
+
+// Returns specifies the output parameters of the declared call
+func (d Decl0x3[R1, R2, R3]) Returns(r1 R1, r2 R2, r3 R3) {
+	d.decl.Return(r1, r2, r3)
+}
+
+// Decl1x0 is a typed declaration of a method with 1 argument(s) and 0 result(s)
+type Decl1x0[A1 any] struct {
+	decl *callDeclaration
+}
+
+// On1x0 is a typed version of OnCall for a method with 1 argument(s) and 0 result(s)
+func On1x0[I, A1 any](obj interface{}, f func(I, A1)) Decl1x0[A1] {
+	return Decl1x0[A1]{declaration(OnCall(obj, f))}
+}
+
+// Expect1x0 is a typed version of ExpectCall for a method with 1 argument(s) and 0 result(s)
+func Expect1x0[I, A1 any](obj interface{}, f func(I, A1)) Decl1x0[A1] {
+	return Decl1x0[A1]{declaration(ExpectCall(obj, f))}
+}
+
+// With restricts the declaration to calls with the given arguments
+func (d Decl1x0[A1]) With(a1 A1) Decl1x0[A1] {
+	d.decl.args = []interface{}{a1}
+	return d
+}
+
+// Decl1x1 is a typed declaration of a method with 1 argument(s) and 1 result(s)
+type Decl1x1[A1, R1 any] struct {
+	decl *callDeclaration
+}
+
+// On1x1 is a typed version of OnCall for a method with 1 argument(s) and 1 result(s)
+func On1x1[I, A1, R1 any](obj interface{}, f func(I, A1) R1) Decl1x1[A1, R1] {
+	return Decl1x1[A1, R1]{declaration(OnCall(obj, f))}
+}
+
+// Expect1x1 is a typed version of ExpectCall for a method with 1 argument(s) and 1 result(s)
+func Expect1x1[I, A1, R1 any](obj interface{}, f func(I, A1) R1) Decl1x1[A1, R1] {
+	return Decl1x1[A1, R1]{declaration(ExpectCall(obj, f))}
+}
+
+// With restricts the declaration to calls with the given arguments
+func (d Decl1x1[A1, R1]) With(a1 A1) Decl1x1[A1, R1] {
+	d.decl.args = []interface{}{a1}
+	return d
+}
+
+// Returns specifies the output parameters of the declared call
+func (d Decl1x1[A1, R1]) Returns(r1 R1) {
+	d.decl.Return(r1)
+}
+
+// Decl1x2 is a typed declaration of a method with 1 argument(s) and 2 result(s)
+type Decl1x2[A1, R1, R2 any] struct {
+	decl *callDeclaration
+}
+
+// On1x2 is a typed version of OnCall for a method with 1 argument(s) and 2 result(s)
+func On1x2[I, A1, R1, R2 any](obj interface{}, f func(I, A1) (R1, R2)) Decl1x2[A1, R1, R2] {
+	return Decl1x2[A1, R1, R2]{declaration(OnCall(obj, f))}
+}
+
+// Expect1x2 is a typed version of ExpectCall for a method with 1 argument(s) and 2 result(s)
+func Expect1x2[I, A1, R1, R2 any](obj interface{}, f func(I, A1) (R1, R2)) Decl1x2[A1, R1, R2] {
+	return Decl1x2[A1, R1, R2]{declaration(ExpectCall(obj, f))}
+}
+
+// With restricts the declaration to calls with the given arguments
+func (d Decl1x2[A1, R1, R2]) With(a1 A1) Decl1x2[A1, R1, R2] {
+	d.decl.args = []interface{}{a1}
+	return d
+}
+
+// Returns specifies the output parameters of the declared call
+func (d Decl1x2[A1, R1, R2]) Returns(r1 R1, r2 R2) {
+	d.decl.Return(r1, r2)
+}
+
+// Decl1x3 is a typed declaration of a method with 1 argument(s) and 3 result(s)
+type Decl1x3[A1, R1, R2, R3 any] struct {
+	decl *callDeclaration
+}
+
+// On1x3 is a typed version of OnCall for a method with 1 argument(s) and 3 result(s)
+func On1x3[I, A1, R1, R2, R3 any](obj interface{}, f func(I, A1) (R1, R2, R3)) Decl1x3[A1, R1, R2, R3] {
+	return Decl1x3[A1, R1, R2, R3]{declaration(OnCall(obj, f))}
+}
+
+// Expect1x3 is a typed version of ExpectCall for a method with 1 argument(s) and 3 result(s)
+func Expect1x3[I, A1, R1, R2, R3 any](obj interface{}, f func(I, A1) (R1, R2, R3)) Decl1x3[A1, R1, R2, R3] {
+	return Decl1x3[A1, R1, R2, R3]{declaration(ExpectCall(obj, f))}
+}
+
+// With restricts the declaration to calls with the given arguments
+func (d Decl1x3[A1, R1, R2, R3]) With(a1 A1) Decl1x3[A1, R1, R2, R3] {
+	d.decl.args = []interface{}{a1}
+	return d
+}
+
+// Returns specifies the output parameters of the declared call
+func (d Decl1x3[A1, R1, R2, R3]) Returns(r1 R1, r2 R2, r3 R3) {
+	d.decl.Return(r1, r2, r3)
+}
+
+// Decl2x0 is a typed declaration of a method with 2 argument(s) and 0 result(s)
+type Decl2x0[A1, A2 any] struct {
+	decl *callDeclaration
+}
+
+// On2x0 is a typed version of OnCall for a method with 2 argument(s) and 0 result(s)
+func On2x0[I, A1, A2 any](obj interface{}, f func(I, A1, A2)) Decl2x0[A1, A2] {
+	return Decl2x0[A1, A2]{declaration(OnCall(obj, f))}
+}
+
+// Expect2x0 is a typed version of ExpectCall for a method with 2 argument(s) and 0 result(s)
+func Expect2x0[I, A1, A2 any](obj interface{}, f func(I, A1, A2)) Decl2x0[A1, A2] {
+	return Decl2x0[A1, A2]{declaration(ExpectCall(obj, f))}
+}
+
+// With restricts the declaration to calls with the given arguments
+func (d Decl2x0[A1, A2]) With(a1 A1, a2 A2) Decl2x0[A1, A2] {
+	d.decl.args = []interface{}{a1, a2}
+	return d
+}
+
+// Decl2x1 is a typed declaration of a method with 2 argument(s) and 1 result(s)
+type Decl2x1[A1, A2, R1 any] struct {
+	decl *callDeclaration
+}
+
+// On2x1 is a typed version of OnCall for a method with 2 argument(s) and 1 result(s)
+func On2x1[I, A1, A2, R1 any](obj interface{}, f func(I, A1, A2) R1) Decl2x1[A1, A2, R1] {
+	return Decl2x1[A1, A2, R1]{declaration(OnCall(obj, f))}
+}
+
+// Expect2x1 is a typed version of ExpectCall for a method with 2 argument(s) and 1 result(s)
+func Expect2x1[I, A1, A2, R1 any](obj interface{}, f func(I, A1, A2) R1) Decl2x1[A1, A2, R1] {
+	return Decl2x1[A1, A2, R1]{declaration(ExpectCall(obj, f))}
+}
+
+// With restricts the declaration to calls with the given arguments
+func (d Decl2x1[A1, A2, R1]) With(a1 A1, a2 A2) Decl2x1[A1, A2, R1] {
+	d.decl.args = []interface{}{a1, a2}
+	return d
+}
+
+// Returns specifies the output parameters of the declared call
+func (d Decl2x1[A1, A2, R1]) Returns(r1 R1) {
+	d.decl.Return(r1)
+}
+
+// Decl2x2 is a typed declaration of a method with 2 argument(s) and 2 result(s)
+type Decl2x2[A1, A2, R1, R2 any] struct {
+	decl *callDeclaration
+}
+
+// On2x2 is a typed version of OnCall for a method with 2 argument(s) and 2 result(s)
+func On2x2[I, A1, A2, R1, R2 any](obj interface{}, f func(I, A1, A2) (R1, R2)) Decl2x2[A1, A2, R1, R2] {
+	return Decl2x2[A1, A2, R1, R2]{declaration(OnCall(obj, f))}
+}
+
+// Expect2x2 is a typed version of ExpectCall for a method with 2 argument(s) and 2 result(s)
+func Expect2x2[I, A1, A2, R1, R2 any](obj interface{}, f func(I, A1, A2) (R1, R2)) Decl2x2[A1, A2, R1, R2] {
+	return Decl2x2[A1, A2, R1, R2]{declaration(ExpectCall(obj, f))}
+}
+
+// With restricts the declaration to calls with the given arguments
+func (d Decl2x2[A1, A2, R1, R2]) With(a1 A1, a2 A2) Decl2x2[A1, A2, R1, R2] {
+	d.decl.args = []interface{}{a1, a2}
+	return d
+}
+
+// Returns specifies the output parameters of the declared call
+func (d Decl2x2[A1, A2, R1, R2]) Returns(r1 R1, r2 R2) {
+	d.decl.Return(r1, r2)
+}
+
+// Decl2x3 is a typed declaration of a method with 2 argument(s) and 3 result(s)
+type Decl2x3[A1, A2, R1, R2, R3 any] struct {
+	decl *callDeclaration
+}
+
+// On2x3 is a typed version of OnCall for a method with 2 argument(s) and 3 result(s)
+func On2x3[I, A1, A2, R1, R2, R3 any](obj interface{}, f func(I, A1, A2) (R1, R2, R3)) Decl2x3[A1, A2, R1, R2, R3] {
+	return Decl2x3[A1, A2, R1, R2, R3]{declaration(OnCall(obj, f))}
+}
+
+// Expect2x3 is a typed version of ExpectCall for a method with 2 argument(s) and 3 result(s)
+func Expect2x3[I, A1, A2, R1, R2, R3 any](obj interface{}, f func(I, A1, A2) (R1, R2, R3)) Decl2x3[A1, A2, R1, R2, R3] {
+	return Decl2x3[A1, A2, R1, R2, R3]{declaration(ExpectCall(obj, f))}
+}
+
+// With restricts the declaration to calls with the given arguments
+func (d Decl2x3[A1, A2, R1, R2, R3]) With(a1 A1, a2 A2) Decl2x3[A1, A2, R1, R2, R3] {
+	d.decl.args = []interface{}{a1, a2}
+	return d
+}
+
+// Returns specifies the output parameters of the declared call
+func (d Decl2x3[A1, A2, R1, R2, R3]) Returns(r1 R1, r2 R2, r3 R3) {
+	d.decl.Return(r1, r2, r3)
+}
+
+// Decl3x0 is a typed declaration of a method with 3 argument(s) and 0 result(s)
+type Decl3x0[A1, A2, A3 any] struct {
+	decl *callDeclaration
+}
+
+// On3x0 is a typed version of OnCall for a method with 3 argument(s) and 0 result(s)
+func On3x0[I, A1, A2, A3 any](obj interface{}, f func(I, A1, A2, A3)) Decl3x0[A1, A2, A3] {
+	return Decl3x0[A1, A2, A3]{declaration(OnCall(obj, f))}
+}
+
+// Expect3x0 is a typed version of ExpectCall for a method with 3 argument(s) and 0 result(s)
+func Expect3x0[I, A1, A2, A3 any](obj interface{}, f func(I, A1, A2, A3)) Decl3x0[A1, A2, A3] {
+	return Decl3x0[A1, A2, A3]{declaration(ExpectCall(obj, f))}
+}
+
+// With restricts the declaration to calls with the given arguments
+func (d Decl3x0[A1, A2, A3]) With(a1 A1, a2 A2, a3 A3) Decl3x0[A1, A2, A3] {
+	d.decl.args = []interface{}{a1, a2, a3}
+	return d
+}
+
+// Decl3x1 is a typed declaration of a method with 3 argument(s) and 1 result(s)
+type Decl3x1[A1, A2, A3, R1 any] struct {
+	decl *callDeclaration
+}
+
+// On3x1 is a typed version of OnCall for a method with 3 argument(s) and 1 result(s)
+func On3x1[I, A1, A2, A3, R1 any](obj interface{}, f func(I, A1, A2, A3) R1) Decl3x1[A1, A2, A3, R1] {
+	return Decl3x1[A1, A2, A3, R1]{declaration(OnCall(obj, f))}
+}
+
+// Expect3x1 is a typed version of ExpectCall for a method with 3 argument(s) and 1 result(s)
+func Expect3x1[I, A1, A2, A3, R1 any](obj interface{}, f func(I, A1, A2, A3) R1) Decl3x1[A1, A2, A3, R1] {
+	return Decl3x1[A1, A2, A3, R1]{declaration(ExpectCall(obj, f))}
+}
+
+// With restricts the declaration to calls with the given arguments
+func (d Decl3x1[A1, A2, A3, R1]) With(a1 A1, a2 A2, a3 A3) Decl3x1[A1, A2, A3, R1] {
+	d.decl.args = []interface{}{a1, a2, a3}
+	return d
+}
+
+// Returns specifies the output parameters of the declared call
+func (d Decl3x1[A1, A2, A3, R1]) Returns(r1 R1) {
+	d.decl.Return(r1)
+}
+
+// Decl3x2 is a typed declaration of a method with 3 argument(s) and 2 result(s)
+type Decl3x2[A1, A2, A3, R1, R2 any] struct {
+	decl *callDeclaration
+}
+
+// On3x2 is a typed version of OnCall for a method with 3 argument(s) and 2 result(s)
+func On3x2[I, A1, A2, A3, R1, R2 any](obj interface{}, f func(I, A1, A2, A3) (R1, R2)) Decl3x2[A1, A2, A3, R1, R2] {
+	return Decl3x2[A1, A2, A3, R1, R2]{declaration(OnCall(obj, f))}
+}
+
+// Expect3x2 is a typed version of ExpectCall for a method with 3 argument(s) and 2 result(s)
+func Expect3x2[I, A1, A2, A3, R1, R2 any](obj interface{}, f func(I, A1, A2, A3) (R1, R2)) Decl3x2[A1, A2, A3, R1, R2] {
+	return Decl3x2[A1, A2, A3, R1, R2]{declaration(ExpectCall(obj, f))}
+}
+
+// With restricts the declaration to calls with the given arguments
+func (d Decl3x2[A1, A2, A3, R1, R2]) With(a1 A1, a2 A2, a3 A3) Decl3x2[A1, A2, A3, R1, R2] {
+	d.decl.args = []interface{}{a1, a2, a3}
+	return d
+}
+
+// Returns specifies the output parameters of the declared call
+func (d Decl3x2[A1, A2, A3, R1, R2]) Returns(r1 R1, r2 R2) {
+	d.decl.Return(r1, r2)
+}
+
+// Decl3x3 is a typed declaration of a method with 3 argument(s) and 3 result(s)
+type Decl3x3[A1, A2, A3, R1, R2, R3 any] struct {
+	decl *callDeclaration
+}
+
+// On3x3 is a typed version of OnCall for a method with 3 argument(s) and 3 result(s)
+func On3x3[I, A1, A2, A3, R1, R2, R3 any](obj interface{}, f func(I, A1, A2, A3) (R1, R2, R3)) Decl3x3[A1, A2, A3, R1, R2, R3] {
+	return Decl3x3[A1, A2, A3, R1, R2, R3]{declaration(OnCall(obj, f))}
+}
+
+// Expect3x3 is a typed version of ExpectCall for a method with 3 argument(s) and 3 result(s)
+func Expect3x3[I, A1, A2, A3, R1, R2, R3 any](obj interface{}, f func(I, A1, A2, A3) (R1, R2, R3)) Decl3x3[A1, A2, A3, R1, R2, R3] {
+	return Decl3x3[A1, A2, A3, R1, R2, R3]{declaration(ExpectCall(obj, f))}
+}
+
+// With restricts the declaration to calls with the given arguments
+func (d Decl3x3[A1, A2, A3, R1, R2, R3]) With(a1 A1, a2 A2, a3 A3) Decl3x3[A1, A2, A3, R1, R2, R3] {
+	d.decl.args = []interface{}{a1, a2, a3}
+	return d
+}
+
+// Returns specifies the output parameters of the declared call
+func (d Decl3x3[A1, A2, A3, R1, R2, R3]) Returns(r1 R1, r2 R2, r3 R3) {
+	d.decl.Return(r1, r2, r3)
+}
+
+// Decl4x0 is a typed declaration of a method with 4 argument(s) and 0 result(s)
+type Decl4x0[A1, A2, A3, A4 any] struct {
+	decl *callDeclaration
+}
+
+// On4x0 is a typed version of OnCall for a method with 4 argument(s) and 0 result(s)
+func On4x0[I, A1, A2, A3, A4 any](obj interface{}, f func(I, A1, A2, A3, A4)) Decl4x0[A1, A2, A3, A4] {
+	return Decl4x0[A1, A2, A3, A4]{declaration(OnCall(obj, f))}
+}
+
+// Expect4x0 is a typed version of ExpectCall for a method with 4 argument(s) and 0 result(s)
+func Expect4x0[I, A1, A2, A3, A4 any](obj interface{}, f func(I, A1, A2, A3, A4)) Decl4x0[A1, A2, A3, A4] {
+	return Decl4x0[A1, A2, A3, A4]{declaration(ExpectCall(obj, f))}
+}
+
+// With restricts the declaration to calls with the given arguments
+func (d Decl4x0[A1, A2, A3, A4]) With(a1 A1, a2 A2, a3 A3, a4 A4) Decl4x0[A1, A2, A3, A4] {
+	d.decl.args = []interface{}{a1, a2, a3, a4}
+	return d
+}
+
+// Decl4x1 is a typed declaration of a method with 4 argument(s) and 1 result(s)
+type Decl4x1[A1, A2, A3, A4, R1 any] struct {
+	decl *callDeclaration
+}
+
+// On4x1 is a typed version of OnCall for a method with 4 argument(s) and 1 result(s)
+func On4x1[I, A1, A2, A3, A4, R1 any](obj interface{}, f func(I, A1, A2, A3, A4) R1) Decl4x1[A1, A2, A3, A4, R1] {
+	return Decl4x1[A1, A2, A3, A4, R1]{declaration(OnCall(obj, f))}
+}
+
+// Expect4x1 is a typed version of ExpectCall for a method with 4 argument(s) and 1 result(s)
+func Expect4x1[I, A1, A2, A3, A4, R1 any](obj interface{}, f func(I, A1, A2, A3, A4) R1) Decl4x1[A1, A2, A3, A4, R1] {
+	return Decl4x1[A1, A2, A3, A4, R1]{declaration(ExpectCall(obj, f))}
+}
+
+// With restricts the declaration to calls with the given arguments
+func (d Decl4x1[A1, A2, A3, A4, R1]) With(a1 A1, a2 A2, a3 A3, a4 A4) Decl4x1[A1, A2, A3, A4, R1] {
+	d.decl.args = []interface{}{a1, a2, a3, a4}
+	return d
+}
+
+// Returns specifies the output parameters of the declared call
+func (d Decl4x1[A1, A2, A3, A4, R1]) Returns(r1 R1) {
+	d.decl.Return(r1)
+}
+
+// Decl4x2 is a typed declaration of a method with 4 argument(s) and 2 result(s)
+type Decl4x2[A1, A2, A3, A4, R1, R2 any] struct {
+	decl *callDeclaration
+}
+
+// On4x2 is a typed version of OnCall for a method with 4 argument(s) and 2 result(s)
+func On4x2[I, A1, A2, A3, A4, R1, R2 any](obj interface{}, f func(I, A1, A2, A3, A4) (R1, R2)) Decl4x2[A1, A2, A3, A4, R1, R2] {
+	return Decl4x2[A1, A2, A3, A4, R1, R2]{declaration(OnCall(obj, f))}
+}
+
+// Expect4x2 is a typed version of ExpectCall for a method with 4 argument(s) and 2 result(s)
+func Expect4x2[I, A1, A2, A3, A4, R1, R2 any](obj interface{}, f func(I, A1, A2, A3, A4) (R1, R2)) Decl4x2[A1, A2, A3, A4, R1, R2] {
+	return Decl4x2[A1, A2, A3, A4, R1, R2]{declaration(ExpectCall(obj, f))}
+}
+
+// With restricts the declaration to calls with the given arguments
+func (d Decl4x2[A1, A2, A3, A4, R1, R2]) With(a1 A1, a2 A2, a3 A3, a4 A4) Decl4x2[A1, A2, A3, A4, R1, R2] {
+	d.decl.args = []interface{}{a1, a2, a3, a4}
+	return d
+}
+
+// Returns specifies the output parameters of the declared call
+func (d Decl4x2[A1, A2, A3, A4, R1, R2]) Returns(r1 R1, r2 R2) {
+	d.decl.Return(r1, r2)
+}
+
+// Decl4x3 is a typed declaration of a method with 4 argument(s) and 3 result(s)
+type Decl4x3[A1, A2, A3, A4, R1, R2, R3 any] struct {
+	decl *callDeclaration
+}
+
+// On4x3 is a typed version of OnCall for a method with 4 argument(s) and 3 result(s)
+func On4x3[I, A1, A2, A3, A4, R1, R2, R3 any](obj interface{}, f func(I, A1, A2, A3, A4) (R1, R2, R3)) Decl4x3[A1, A2, A3, A4, R1, R2, R3] {
+	return Decl4x3[A1, A2, A3, A4, R1, R2, R3]{declaration(OnCall(obj, f))}
+}
+
+// Expect4x3 is a typed version of ExpectCall for a method with 4 argument(s) and 3 result(s)
+func Expect4x3[I, A1, A2, A3, A4, R1, R2, R3 any](obj interface{}, f func(I, A1, A2, A3, A4) (R1, R2, R3)) Decl4x3[A1, A2, A3, A4, R1, R2, R3] {
+	return Decl4x3[A1, A2, A3, A4, R1, R2, R3]{declaration(ExpectCall(obj, f))}
+}
+
+// With restricts the declaration to calls with the given arguments
+func (d Decl4x3[A1, A2, A3, A4, R1, R2, R3]) With(a1 A1, a2 A2, a3 A3, a4 A4) Decl4x3[A1, A2, A3, A4, R1, R2, R3] {
+	d.decl.args = []interface{}{a1, a2, a3, a4}
+	return d
+}
+
+// Returns specifies the output parameters of the declared call
+func (d Decl4x3[A1, A2, A3, A4, R1, R2, R3]) Returns(r1 R1, r2 R2, r3 R3) {
+	d.decl.Return(r1, r2, r3)
+}
+
+// Decl5x0 is a typed declaration of a method with 5 argument(s) and 0 result(s)
+type Decl5x0[A1, A2, A3, A4, A5 any] struct {
+	decl *callDeclaration
+}
+
+// On5x0 is a typed version of OnCall for a method with 5 argument(s) and 0 result(s)
+func On5x0[I, A1, A2, A3, A4, A5 any](obj interface{}, f func(I, A1, A2, A3, A4, A5)) Decl5x0[A1, A2, A3, A4, A5] {
+	return Decl5x0[A1, A2, A3, A4, A5]{declaration(OnCall(obj, f))}
+}
+
+// Expect5x0 is a typed version of ExpectCall for a method with 5 argument(s) and 0 result(s)
+func Expect5x0[I, A1, A2, A3, A4, A5 any](obj interface{}, f func(I, A1, A2, A3, A4, A5)) Decl5x0[A1, A2, A3, A4, A5] {
+	return Decl5x0[A1, A2, A3, A4, A5]{declaration(ExpectCall(obj, f))}
+}
+
+// With restricts the declaration to calls with the given arguments
+func (d Decl5x0[A1, A2, A3, A4, A5]) With(a1 A1, a2 A2, a3 A3, a4 A4, a5 A5) Decl5x0[A1, A2, A3, A4, A5] {
+	d.decl.args = []interface{}{a1, a2, a3, a4, a5}
+	return d
+}
+
+// Decl5x1 is a typed declaration of a method with 5 argument(s) and 1 result(s)
+type Decl5x1[A1, A2, A3, A4, A5, R1 any] struct {
+	decl *callDeclaration
+}
+
+// On5x1 is a typed version of OnCall for a method with 5 argument(s) and 1 result(s)
+func On5x1[I, A1, A2, A3, A4, A5, R1 any](obj interface{}, f func(I, A1, A2, A3, A4, A5) R1) Decl5x1[A1, A2, A3, A4, A5, R1] {
+	return Decl5x1[A1, A2, A3, A4, A5, R1]{declaration(OnCall(obj, f))}
+}
+
+// Expect5x1 is a typed version of ExpectCall for a method with 5 argument(s) and 1 result(s)
+func Expect5x1[I, A1, A2, A3, A4, A5, R1 any](obj interface{}, f func(I, A1, A2, A3, A4, A5) R1) Decl5x1[A1, A2, A3, A4, A5, R1] {
+	return Decl5x1[A1, A2, A3, A4, A5, R1]{declaration(ExpectCall(obj, f))}
+}
+
+// With restricts the declaration to calls with the given arguments
+func (d Decl5x1[A1, A2, A3, A4, A5, R1]) With(a1 A1, a2 A2, a3 A3, a4 A4, a5 A5) Decl5x1[A1, A2, A3, A4, A5, R1] {
+	d.decl.args = []interface{}{a1, a2, a3, a4, a5}
+	return d
+}
+
+// Returns specifies the output parameters of the declared call
+func (d Decl5x1[A1, A2, A3, A4, A5, R1]) Returns(r1 R1) {
+	d.decl.Return(r1)
+}
+
+// Decl5x2 is a typed declaration of a method with 5 argument(s) and 2 result(s)
+type Decl5x2[A1, A2, A3, A4, A5, R1, R2 any] struct {
+	decl *callDeclaration
+}
+
+// On5x2 is a typed version of OnCall for a method with 5 argument(s) and 2 result(s)
+func On5x2[I, A1, A2, A3, A4, A5, R1, R2 any](obj interface{}, f func(I, A1, A2, A3, A4, A5) (R1, R2)) Decl5x2[A1, A2, A3, A4, A5, R1, R2] {
+	return Decl5x2[A1, A2, A3, A4, A5, R1, R2]{declaration(OnCall(obj, f))}
+}
+
+// Expect5x2 is a typed version of ExpectCall for a method with 5 argument(s) and 2 result(s)
+func Expect5x2[I, A1, A2, A3, A4, A5, R1, R2 any](obj interface{}, f func(I, A1, A2, A3, A4, A5) (R1, R2)) Decl5x2[A1, A2, A3, A4, A5, R1, R2] {
+	return Decl5x2[A1, A2, A3, A4, A5, R1, R2]{declaration(ExpectCall(obj, f))}
+}
+
+// With restricts the declaration to calls with the given arguments
+func (d Decl5x2[A1, A2, A3, A4, A5, R1, R2]) With(a1 A1, a2 A2, a3 A3, a4 A4, a5 A5) Decl5x2[A1, A2, A3, A4, A5, R1, R2] {
+	d.decl.args = []interface{}{a1, a2, a3, a4, a5}
+	return d
+}
+
+// Returns specifies the output parameters of the declared call
+func (d Decl5x2[A1, A2, A3, A4, A5, R1, R2]) Returns(r1 R1, r2 R2) {
+	d.decl.Return(r1, r2)
+}
+
+// Decl5x3 is a typed declaration of a method with 5 argument(s) and 3 result(s)
+type Decl5x3[A1, A2, A3, A4, A5, R1, R2, R3 any] struct {
+	decl *callDeclaration
+}
+
+// On5x3 is a typed version of OnCall for a method with 5 argument(s) and 3 result(s)
+func On5x3[I, A1, A2, A3, A4, A5, R1, R2, R3 any](obj interface{}, f func(I, A1, A2, A3, A4, A5) (R1, R2, R3)) Decl5x3[A1, A2, A3, A4, A5, R1, R2, R3] {
+	return Decl5x3[A1, A2, A3, A4, A5, R1, R2, R3]{declaration(OnCall(obj, f))}
+}
+
+// Expect5x3 is a typed version of ExpectCall for a method with 5 argument(s) and 3 result(s)
+func Expect5x3[I, A1, A2, A3, A4, A5, R1, R2, R3 any](obj interface{}, f func(I, A1, A2, A3, A4, A5) (R1, R2, R3)) Decl5x3[A1, A2, A3, A4, A5, R1, R2, R3] {
+	return Decl5x3[A1, A2, A3, A4, A5, R1, R2, R3]{declaration(ExpectCall(obj, f))}
+}
+
+// With restricts the declaration to calls with the given arguments
+func (d Decl5x3[A1, A2, A3, A4, A5, R1, R2, R3]) With(a1 A1, a2 A2, a3 A3, a4 A4, a5 A5) Decl5x3[A1, A2, A3, A4, A5, R1, R2, R3] {
+	d.decl.args = []interface{}{a1, a2, a3, a4, a5}
+	return d
+}
+
+// Returns specifies the output parameters of the declared call
+func (d Decl5x3[A1, A2, A3, A4, A5, R1, R2, R3]) Returns(r1 R1, r2 R2, r3 R3) {
+	d.decl.Return(r1, r2, r3)
+}
@@ -0,0 +1,83 @@
+package mock
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestTypedOnCall(t *testing.T) {
+	obj := &myObj{New(t)}
+
+	out1 := &myType{"data"}
+	out2 := fmt.Errorf("error")
+	On1x2(obj, myInterface.doSmth).With(123).Returns(out1, out2)
+
+	v, err := obj.doSmth(123)
+
+	if !reflect.DeepEqual(v, out1) {
+		t.Fatal("!reflect.DeepEqual(v, out1)")
+	}
+
+	if !reflect.DeepEqual(err, out2) {
+		t.Fatal("!reflect.DeepEqual(err, out2)")
+	}
+}
+
+func TestTypedOnCallWithAnyArgs(t *testing.T) {
+	obj := &myObj{New(t)}
+
+	On1x2(obj, myInterface.doSmth).Returns(nil, nil)
+
+	if v, err := obj.doSmth(321); v != nil || err != nil {
+		t.Fatal("v != nil || err != nil")
+	}
+}
+
+func TestTypedOnCallWithInvalidArgs(t *testing.T) {
+	tm := new(tmock)
+	obj := &myObj{New(tm)}
+
+	On1x2(obj, myInterface.doSmth).With(123)
+
+	obj.doSmth(321)
+
+	if !tm.fail {
+		t.Fatal("!tm.fail")
+	}
+}
+
+func TestTypedExpectCall(t *testing.T) {
+	m := New(t)
+	obj := &myObj{m}
+
+	Expect0x1(obj, myInterface.doSmth2).Returns(myType{"data"})
+	Expect1x1(obj, myInterface.slice).With(nil).Returns([]int{1})
+
+	v := obj.doSmth2()
+	out := obj.slice(nil)
+
+	m.CheckExpectations()
+
+	if !reflect.DeepEqual(v, myType{"data"}) {
+		t.Fatal(`!reflect.DeepEqual(v, myType{"data"})`)
+	}
+
+	if !reflect.DeepEqual(out, []int{1}) {
+		t.Fatal(`!reflect.DeepEqual(out, []int{1})`)
+	}
+}
+
+func TestTypedExpectCallNotCalled(t *testing.T) {
+	tm := new(tmock)
+	m := New(tm)
+	obj := &myObj{m}
+
+	Expect1x2(obj, myInterface.doSmth).With(1)
+
+	m.CheckExpectations()
+
+	if !tm.fail {
+		t.Fatal("!tm.fail")
+	}
+}
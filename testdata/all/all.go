@@ -0,0 +1,23 @@
+// Package all has interfaces generated with -all.
+package all
+
+// Reader reads values.
+type Reader interface {
+	Read(key string) (string, error)
+}
+
+// Writer writes values.
+type Writer interface {
+	Write(key, val string) error
+}
+
+// Internal can be mocked in this package only.
+type Internal interface {
+	Do()
+	do()
+}
+
+// Number is a constraint.
+type Number interface {
+	~int | ~float64
+}
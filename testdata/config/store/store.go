@@ -0,0 +1,12 @@
+// Package store has interfaces generated by configs.
+package store
+
+// Getter gets values.
+type Getter interface {
+	Get(key string) (string, error)
+}
+
+// Setter sets values.
+type Setter interface {
+	Set(key, val string) error
+}